@@ -8,7 +8,8 @@ import (
 //go:embed mr_comment.md
 var MrCommentContent embed.FS
 
-// MRCommentTemplate loads the embedded mr_comment.md as a string.
+// MRCommentTemplate loads the embedded mr_comment.md as a string. It is a
+// text/template; gitlab renders it with the release-type selection and forecast.
 func MRCommentTemplate() string {
 	data, err := MrCommentContent.ReadFile("mr_comment.md")
 	if err != nil {
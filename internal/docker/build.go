@@ -2,6 +2,7 @@
 package docker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -10,17 +11,19 @@ import (
 	"syac/internal/executil"
 )
 
-func BuildAndPush(opts *BuildOptions) error {
-	if err := BuildImage(opts); err != nil {
+// BuildAndPush builds the image and, when opts.Push is set, pushes it. docker
+// is killed if ctx ends first (see runtime.RunContext).
+func BuildAndPush(ctx context.Context, opts *BuildOptions) error {
+	if err := BuildImage(ctx, opts); err != nil {
 		return err
 	}
 	if opts.Push {
-		return PushImage(opts)
+		return PushImage(ctx, opts)
 	}
 	return nil
 }
 
-func BuildImage(opts *BuildOptions) error {
+func BuildImage(ctx context.Context, opts *BuildOptions) error {
 	if opts == nil {
 		return errors.New("BuildImage: opts is nil")
 	}
@@ -111,5 +114,5 @@ func BuildImage(opts *BuildOptions) error {
 	if opts.DryRun {
 		return executil.DryRunCMD("docker", args...)
 	}
-	return executil.RunCMD("docker", args...)
+	return executil.RunCtx(ctx, "docker", args...)
 }
@@ -22,7 +22,7 @@ import (
 //
 // Steps:
 //   - validate required context values (registry, app name)
-//   - read env overrides (Dockerfile path, context dir)
+//   - read component/env overrides (Dockerfile path, context dir)
 //   - resolve flow (feature, MR, default, release)
 //   - run PlanBuild to decide tags and push policy
 //   - prepare standard build args for Dockerfile
@@ -37,9 +37,9 @@ func BuildOptionsFromContext(c *runtime.Context) (*BuildOptions, error) {
 		return nil, fmt.Errorf("ApplicationName is empty (set SYAC_APPLICATION_NAME or CI_REGISTRY_IMAGE last segment)")
 	}
 
-	// Inputs: Dockerfile + build context (can be overridden via env)
-	df := getenv("SYAC_DOCKERFILE", "Dockerfile")
-	ctxPath := getenv("SYAC_BUILD_CONTEXT", ".")
+	// Inputs: Dockerfile + build context (component paths win, then env overrides)
+	df := first(c.Dockerfile, getenv("SYAC_DOCKERFILE", "Dockerfile"))
+	ctxPath := first(c.BuildContext, getenv("SYAC_BUILD_CONTEXT", "."))
 
 	// Resolve flow and generate a build plan (tags + push policy)
 	flow := runtime.ResolveFlow(*c, runtime.FlowAuto)
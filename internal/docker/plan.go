@@ -6,10 +6,19 @@
 // Rules (semver-first RC):
 //   - feature  → :<shortsha> [ + :latest if SYAC_LATEST_ON_FEATURE=true ]
 //                 push only if PUSH_FEATURE=true
-//   - mr       → :<shortsha>, :<next-rc-with-shortsha> (always push)
-//   - default  → :<shortsha>, :<next-rc-with-shortsha>, :<branch>
+//   - mr       → :<shortsha>, :<next>-rc.N (always push)
+//   - default  → :<shortsha>, :<next>-rc.N, :<branch>
 //                 [ + :latest if SYAC_LATEST_ON_DEFAULT=true ]
-//   - release  → :<tag> [ + :latest if SYAC_TAG_LATEST=true ]
+//   - maint.   → :<shortsha>, :<line-next>-rc.N, :<branch> (e.g. release-1.4)
+//                 never :latest — an old line must not move it
+//   - release  → :<tag> [ + :latest if SYAC_TAG_LATEST=true and <tag> is not an RC ]
+//
+// A commit message with [syac skip-push] never pushes (see runtime.Directives).
+//
+// RC numbers are allocated from existing <next>-rc.N git tags (see
+// TagsService.GetNextPreRelease); the short SHA tag is always emitted separately.
+// Version tags are the scheme-formatted version, not the git tag name
+// (component tag api/1.2.3 → :1.2.3, see runtime.Context.ImageVersion).
 //
 // This keeps policy isolated and testable; BuildOptionsFromContext
 // just calls into here.
@@ -22,6 +31,7 @@ import (
 	"strings"
 
 	"syac/internal/runtime"
+	"syac/internal/version"
 )
 
 // Plan is the output of the planner: tags + push flag.
@@ -60,32 +70,38 @@ func PlanBuild(ctx runtime.Context, flow runtime.Flow) Plan {
 		}
 
 	case runtime.FlowMR:
-		// Short SHA + semver-based RC
+		// Short SHA + numbered RC
 		add(&refs, ctx.ShortSHA)
-		add(&refs, ctx.NextRCVersion)
+		add(&refs, ctx.ImageVersion(ctx.NextRCVersion))
 
 	case runtime.FlowDefault:
 		// Default branch: short SHA, RC, and channel tag
 		add(&refs, ctx.ShortSHA)
-		add(&refs, ctx.NextRCVersion)
+		add(&refs, ctx.ImageVersion(ctx.NextRCVersion))
 		add(&refs, ctx.DefaultBranch)
 		// Optional "latest" on default branch
 		if os.Getenv("SYAC_LATEST_ON_DEFAULT") == "true" {
 			add(&refs, "latest")
 		}
 
+	case runtime.FlowMaintenance:
+		// Maintenance line: short SHA, patch RC on the line, and branch channel tag
+		add(&refs, ctx.ShortSHA)
+		add(&refs, ctx.ImageVersion(ctx.NextRCVersion))
+		add(&refs, first(ctx.EffectiveRef, ctx.RefName))
+
 	case runtime.FlowRelease:
 		// Final release tag
-		add(&refs, ctx.Tag)
-		// Optional "latest" on release (common practice)
-		if os.Getenv("SYAC_TAG_LATEST") == "true" {
+		add(&refs, ctx.ImageVersion(ctx.Tag))
+		// Optional "latest" on release (common practice); RC tag pipelines never move it
+		if os.Getenv("SYAC_TAG_LATEST") == "true" && !isPreReleaseTag(ctx.TagFormat, ctx.Tag) {
 			add(&refs, "latest")
 		}
 
 	default:
 		// Fallback: behave like default branch
 		add(&refs, ctx.ShortSHA)
-		add(&refs, ctx.NextRCVersion)
+		add(&refs, ctx.ImageVersion(ctx.NextRCVersion))
 		if ctx.IsDefaultBranch && ctx.DefaultBranch != "" {
 			add(&refs, ctx.DefaultBranch)
 		}
@@ -94,11 +110,22 @@ func PlanBuild(ctx runtime.Context, flow runtime.Flow) Plan {
 	// Deduplicate to keep tags clean and deterministic
 	refs = dedupRefs(refs)
 
-	// Push policy: features are gated, everything else always pushes
+	// Push policy: features are gated, everything else always pushes,
+	// unless the commit says [syac skip-push]
 	push := true
 	if flow == runtime.FlowFeature && strings.EqualFold(strings.TrimSpace(ctx.Source), "push") {
 		push = getenv("PUSH_FEATURE", "") == "true"
 	}
+	if ctx.Directives.SkipPush {
+		push = false
+	}
 
 	return Plan{Refs: refs, Push: push}
 }
+
+// isPreReleaseTag reports whether a git tag is a SemVer pre-release (e.g. 1.4.3-rc.2)
+// in the project's tag format.
+func isPreReleaseTag(format version.TagFormat, tag string) bool {
+	v, err := format.Parse(strings.TrimSpace(tag))
+	return err == nil && v.IsPreRelease()
+}
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"slices"
+	"testing"
+
+	"syac/internal/runtime"
+)
+
+func TestPlanBuildComponentUsesVersionNotTagName(t *testing.T) {
+	t.Setenv("SYAC_TAG_FORMAT", "")
+	t.Setenv("SYAC_VERSION_SCHEME", "")
+	t.Setenv("SYAC_TAG_LATEST", "")
+	t.Setenv("SYAC_LATEST_ON_DEFAULT", "")
+
+	base := runtime.Context{
+		RegistryImage:   "registry.example.com/group/mono",
+		ApplicationName: "mono",
+		ShortSHA:        "abc12345",
+		DefaultBranch:   "main",
+	}
+	c, err := base.ForComponent(runtime.Component{Name: "api", Paths: []string{"services/api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.NextRCVersion = "api/1.2.3-rc.1"
+	got := PlanBuild(c, runtime.FlowDefault).Refs
+	want := []string{
+		"registry.example.com/group/mono/api:abc12345",
+		"registry.example.com/group/mono/api:1.2.3-rc.1",
+		"registry.example.com/group/mono/api:main",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("default refs = %v, want %v", got, want)
+	}
+
+	c.IsTag, c.Tag = true, "api/1.2.3"
+	if got := PlanBuild(c, runtime.FlowRelease).Refs; !slices.Equal(got, []string{"registry.example.com/group/mono/api:1.2.3"}) {
+		t.Fatalf("release refs = %v, want :1.2.3", got)
+	}
+}
+
+func TestPlanBuildMaintenanceNeverMovesLatest(t *testing.T) {
+	t.Setenv("SYAC_LATEST_ON_DEFAULT", "true")
+
+	c := runtime.Context{
+		RegistryImage:       "registry.example.com/group",
+		ApplicationName:     "app",
+		ShortSHA:            "abc12345",
+		RefName:             "release/1.4",
+		EffectiveRef:        "release/1.4",
+		IsMaintenanceBranch: true,
+		NextRCVersion:       "1.4.3-rc.1",
+	}
+	flow := runtime.ResolveFlow(c, runtime.FlowAuto)
+	if flow != runtime.FlowMaintenance {
+		t.Fatalf("flow = %s, want maintenance", flow)
+	}
+	want := []string{
+		"registry.example.com/group/app:abc12345",
+		"registry.example.com/group/app:1.4.3-rc.1",
+		"registry.example.com/group/app:release-1.4",
+	}
+	if got := PlanBuild(c, flow).Refs; !slices.Equal(got, want) {
+		t.Fatalf("refs = %v, want %v (no :latest)", got, want)
+	}
+}
@@ -11,6 +11,7 @@
 package docker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -20,8 +21,9 @@ import (
 )
 
 // PushImage logs into the GitLab registry and pushes every ref in opts.FullRefs.
-// It respects opts.DryRun (commands are printed, not executed).
-func PushImage(opts *BuildOptions) error {
+// It respects opts.DryRun (commands are printed, not executed) and stops when
+// ctx ends.
+func PushImage(ctx context.Context, opts *BuildOptions) error {
 	if opts == nil {
 		return errors.New("PushImage: opts is nil")
 	}
@@ -40,17 +42,17 @@ func PushImage(opts *BuildOptions) error {
 	}
 
 	// Docker login
-	if err := login(registry, user, password, opts.DryRun); err != nil {
+	if err := login(ctx, registry, user, password, opts.DryRun); err != nil {
 		return fmt.Errorf("docker login failed: %w", err)
 	}
 	if !opts.DryRun {
 		// Only log out if we actually logged in
-		defer logout(registry)
+		defer logout(ctx, registry)
 	}
 
 	// Push each tag
 	for _, r := range refs {
-		if err := pushRef(r, opts.DryRun); err != nil {
+		if err := pushRef(ctx, r, opts.DryRun); err != nil {
 			return err
 		}
 	}
@@ -69,22 +71,23 @@ func credsFromEnv() (registry, user, password string) {
 }
 
 // login runs a docker login (masked if dry-run).
-func login(registry, user, password string, dry bool) error {
+func login(ctx context.Context, registry, user, password string, dry bool) error {
 	if dry {
 		return executil.DryRunCMD("docker", "login", "-u", user, "-p", "[REDACTED]", registry)
 	}
-	return executil.RunCMD("docker", "login", "-u", user, "-p", password, registry)
+	return executil.RunCtx(ctx, "docker", "login", "-u", user, "-p", password, registry)
 }
 
 // logout runs docker logout, but doesn’t fail the pipeline if it errors.
-func logout(registry string) {
-	if err := executil.RunCMD("docker", "logout", registry); err != nil {
+// It still runs when ctx was cancelled so credentials don't outlive the job.
+func logout(ctx context.Context, registry string) {
+	if err := executil.RunCtx(context.WithoutCancel(ctx), "docker", "logout", registry); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: docker logout failed: %v\n", err)
 	}
 }
 
 // pushRef pushes a single tag (respects dry-run).
-func pushRef(ref string, dry bool) error {
+func pushRef(ctx context.Context, ref string, dry bool) error {
 	ref = strings.TrimSpace(ref)
 	if ref == "" {
 		return nil
@@ -93,5 +96,5 @@ func pushRef(ref string, dry bool) error {
 		return executil.DryRunCMD("docker", "push", ref)
 	}
 	fmt.Printf("Pushing image: %s\n", ref)
-	return executil.RunCMD("docker", "push", ref)
+	return executil.RunCtx(ctx, "docker", "push", ref)
 }
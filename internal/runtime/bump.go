@@ -0,0 +1,228 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+)
+
+// BumpSource names one place a bump decision can come from.
+type BumpSource string
+
+const (
+	BumpSourceEnv     BumpSource = "env"     // SYAC_BUMP
+	BumpSourceTrailer BumpSource = "trailer" // Release-Type commit trailer (see Directives)
+	BumpSourceMR      BumpSource = "mr"      // MR release-type checkbox
+	BumpSourceCommits BumpSource = "commits" // Conventional Commits since the latest tag
+)
+
+// defaultBumpSources keeps the historical behavior: SYAC_BUMP, then the MR checkbox,
+// with a commit's own Release-Type trailer in between.
+var defaultBumpSources = []BumpSource{BumpSourceEnv, BumpSourceTrailer, BumpSourceMR}
+
+// BumpCommit is a commit that drove a Conventional Commits bump decision.
+type BumpCommit struct {
+	ShortID string
+	Title   string
+	Bump    version.VersionType
+}
+
+// bumpSources reads SYAC_BUMP_SOURCES (e.g. "env,commits,mr"); the first source
+// that yields a selection wins. Unknown entries are ignored. Within "mr" a
+// release-type label beats the SYAC note, which beats the description block
+// (see MergeRequestsService.ResolveVersionBump).
+func bumpSources() []BumpSource {
+	raw := strings.TrimSpace(os.Getenv("SYAC_BUMP_SOURCES"))
+	if raw == "" {
+		return defaultBumpSources
+	}
+	var out []BumpSource
+	for _, p := range strings.Split(raw, ",") {
+		switch s := BumpSource(strings.ToLower(strings.TrimSpace(p))); s {
+		case BumpSourceEnv, BumpSourceTrailer, BumpSourceMR, BumpSourceCommits:
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return defaultBumpSources
+	}
+	return out
+}
+
+// ResolveBump walks the configured bump sources in order and applies the first hit.
+// Returns a short string describing where the bump came from, or "" if unchanged.
+func (c *Context) ResolveBump(ctx context.Context, client *gitlab.Client) string {
+	for _, src := range bumpSources() {
+		switch src {
+		case BumpSourceEnv:
+			if vt, ok := bumpFromEnv(); ok {
+				c.BumpType = vt
+				return "SYAC_BUMP"
+			}
+		case BumpSourceTrailer:
+			if c.Directives.Bump != "" {
+				c.BumpType = c.Directives.Bump
+				return "Release-Type trailer"
+			}
+		case BumpSourceMR:
+			if vt, ok := c.bumpFromMR(ctx, client); ok {
+				c.BumpType = vt
+				if c.MergedMR != nil {
+					return fmt.Sprintf("merged MR !%d selection", c.MergedMR.IID)
+				}
+				return "MR selection"
+			}
+		case BumpSourceCommits:
+			if vt, commits, ok := c.bumpFromCommits(ctx, client); ok {
+				c.BumpType = vt
+				c.BumpCommits = commits
+				return "conventional commits"
+			}
+		}
+	}
+	return ""
+}
+
+func bumpFromEnv() (version.VersionType, bool) {
+	v := strings.TrimSpace(os.Getenv("SYAC_BUMP"))
+	if v == "" {
+		return "", false
+	}
+	vt, err := version.ParseVersionType(v)
+	if err != nil {
+		return "", false
+	}
+	return vt, true
+}
+
+// bumpLabelsFromEnv reads SYAC_BUMP_LABELS, a comma-separated list of
+// <level>=<label> (e.g. "minor=feature,major=breaking"; unset levels keep
+// gitlab.DefaultBumpLabels), and SYAC_SYNC_BUMP_LABEL=true, which makes the
+// labels mirror the checkbox selection instead of overriding it.
+func bumpLabelsFromEnv() (gitlab.BumpLabels, error) {
+	var labels gitlab.BumpLabels
+	for _, entry := range strings.Split(os.Getenv("SYAC_BUMP_LABELS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		level, label, found := strings.Cut(entry, "=")
+		label = strings.TrimSpace(label)
+		if !found || label == "" {
+			return gitlab.BumpLabels{}, fmt.Errorf("SYAC_BUMP_LABELS: entry %q must be <level>=<label>", entry)
+		}
+		vt, err := version.ParseVersionType(strings.TrimSpace(level))
+		if err != nil {
+			return gitlab.BumpLabels{}, fmt.Errorf("SYAC_BUMP_LABELS: %w", err)
+		}
+		switch vt {
+		case version.Patch:
+			labels.Patch = label
+		case version.Minor:
+			labels.Minor = label
+		case version.Major:
+			labels.Major = label
+		}
+	}
+	labels.Mirror = os.Getenv("SYAC_SYNC_BUMP_LABEL") == "true"
+	return labels, nil
+}
+
+// bumpFromMR reads the release-type checkbox of the MR pipeline's MR or, on
+// default/maintenance branch pushes, of the MR that landed the commit.
+func (c *Context) bumpFromMR(ctx context.Context, client *gitlab.Client) (version.VersionType, bool) {
+	if client == nil {
+		return "", false
+	}
+	mrID := ""
+	if c.IsMergeRequest {
+		mrID = strings.TrimSpace(c.MRID)
+	} else if mr := c.mergedMR(ctx, client); mr != nil {
+		mrID = strconv.Itoa(mr.IID)
+	}
+	if mrID == "" {
+		return "", false
+	}
+	sel, err := c.mrs(client).ResolveVersionBumpCtx(ctx, mrID)
+	if err != nil {
+		return "", false
+	}
+	c.BumpSelection = &sel
+	return sel.Bump, true
+}
+
+// bumpFromCommits lists the commits between the latest semver tag and the current
+// SHA and returns the highest Conventional Commit bump, plus the commits that asked for it.
+// With no tag yet (ErrNoTags) the whole history of the SHA is considered.
+func (c *Context) bumpFromCommits(ctx context.Context, client *gitlab.Client) (version.VersionType, []BumpCommit, bool) {
+	if client == nil || strings.TrimSpace(c.SHA) == "" {
+		return "", nil, false
+	}
+
+	latest, err := c.tags(client).GetLatestTagCtx(ctx)
+	if err != nil && !errors.Is(err, gitlab.ErrNoTags) {
+		fmt.Printf("[bump] warn: tag lookup failed: %v\n", err)
+		return "", nil, false
+	}
+	var commits []gitlab.Commit
+	if errors.Is(err, gitlab.ErrNoTags) {
+		commits, err = client.Commits.ListCommitsCtx(ctx, c.SHA)
+	} else {
+		commits, err = client.Commits.CompareCommitsCtx(ctx, c.TagFormat.Format(latest), c.SHA)
+	}
+	if err != nil {
+		fmt.Printf("[bump] warn: commit history lookup failed: %v\n", err)
+		return "", nil, false
+	}
+
+	var best version.VersionType
+	var drivers []BumpCommit
+	for _, cm := range commits {
+		cc, ok := version.ParseConventionalCommit(cm.Message)
+		if !ok {
+			continue
+		}
+		bump, ok := cc.Bump()
+		if !ok {
+			continue
+		}
+		switch {
+		case bump.Rank() > best.Rank():
+			best = bump
+			drivers = drivers[:0]
+			fallthrough
+		case bump == best:
+			drivers = append(drivers, BumpCommit{ShortID: cm.ShortID, Title: cm.Title, Bump: bump})
+		}
+	}
+	if best == "" {
+		return "", nil, false
+	}
+	return best, drivers, true
+}
+
+// applyBumpPolicy enforces branch rules on the resolved bump (see clampBump).
+// Returns a short note when the bump was changed, or "".
+func (c *Context) applyBumpPolicy() string {
+	if clamped := c.clampBump(c.BumpType); clamped != c.BumpType {
+		was := c.BumpType
+		c.BumpType = clamped
+		return fmt.Sprintf("%s clamped to %s on maintenance line %s.x", was, clamped, c.MaintenanceLine)
+	}
+	return ""
+}
+
+// clampBump returns bump as this context's branch allows it: maintenance
+// lines only ship patches, so Minor/Major become Patch.
+func (c *Context) clampBump(bump version.VersionType) version.VersionType {
+	if c.MaintenanceLine != nil {
+		return version.Patch
+	}
+	return bump
+}
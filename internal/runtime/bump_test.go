@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestResolveBumpFromConventionalCommits(t *testing.T) {
+	t.Setenv("SYAC_BUMP_SOURCES", "env,commits,mr")
+	t.Setenv("SYAC_BUMP", "")
+
+	srv := gitlabtest.NewServer(t)
+	srv.AddCommit(gitlab.Commit{Message: "feat!: old breaking change"})
+	srv.AddTag("2.0.0", "")
+	srv.AddCommit(gitlab.Commit{Message: "fix: a bug"})
+	srv.AddCommit(gitlab.Commit{Message: "docs: readme"})
+	head := srv.AddCommit(gitlab.Commit{Message: "feat(api): new endpoint"})
+
+	ctx := defaultBranchContext(t, head.ID)
+	if src := ctx.ResolveBump(context.Background(), srv.Client()); src != "conventional commits" {
+		t.Fatalf("source = %q, want conventional commits", src)
+	}
+	if ctx.BumpType != version.Minor || len(ctx.BumpCommits) != 1 || ctx.BumpCommits[0].Title != "feat(api): new endpoint" {
+		t.Fatalf("bump=%s commits=%+v, want Minor from the feat(api) commit only", ctx.BumpType, ctx.BumpCommits)
+	}
+}
+
+func TestBumpLabelsFromEnv(t *testing.T) {
+	t.Setenv("SYAC_BUMP_LABELS", "minor=feature, major = breaking")
+	t.Setenv("SYAC_SYNC_BUMP_LABEL", "true")
+	labels, err := bumpLabelsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := gitlab.BumpLabels{Minor: "feature", Major: "breaking", Mirror: true}
+	if labels != want {
+		t.Fatalf("labels = %+v, want %+v", labels, want)
+	}
+
+	t.Setenv("SYAC_BUMP_LABELS", "huge=release::huge")
+	if _, err := bumpLabelsFromEnv(); err == nil {
+		t.Fatal("unknown level should fail")
+	}
+}
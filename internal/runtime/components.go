@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"syac/pkg/gitlab"
+)
+
+// defaultComponentTagFormat namespaces tags per component (api/1.2.3) when
+// SYAC_TAG_FORMAT is not set.
+const defaultComponentTagFormat = "{{.App}}/{{.Version}}"
+
+// Component is one independently versioned deployable in a monorepo.
+// Paths are repo-relative directory prefixes; the first one is also the
+// docker build context (with its Dockerfile).
+type Component struct {
+	Name  string
+	Paths []string
+}
+
+// LoadComponents parses SYAC_COMPONENTS, e.g.
+//
+//	SYAC_COMPONENTS="api:services/api,libs/common;worker:services/worker"
+//
+// Returns nil when unset (single-application repo).
+func LoadComponents() ([]Component, error) {
+	raw := strings.TrimSpace(os.Getenv("SYAC_COMPONENTS"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var out []Component
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, paths, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("SYAC_COMPONENTS: entry %q must be <name>:<path>[,<path>...]", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("SYAC_COMPONENTS: duplicate component %q", name)
+		}
+		seen[name] = true
+
+		comp := Component{Name: name}
+		for _, p := range strings.Split(paths, ",") {
+			if p = cleanComponentPath(p); p != "" {
+				comp.Paths = append(comp.Paths, p)
+			}
+		}
+		if len(comp.Paths) == 0 {
+			return nil, fmt.Errorf("SYAC_COMPONENTS: component %q has no paths", name)
+		}
+		out = append(out, comp)
+	}
+	return out, nil
+}
+
+func cleanComponentPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(strings.TrimPrefix(p, "./"))
+	if p == "." {
+		return "."
+	}
+	return strings.Trim(p, "/")
+}
+
+// Owns reports whether a repo-relative file path falls under one of the component's paths.
+func (c Component) Owns(file string) bool {
+	file = strings.TrimPrefix(file, "./")
+	for _, p := range c.Paths {
+		if p == "." || file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ForComponent derives a per-component Context: the component name becomes the
+// application name (image path and {{.App}} in tags), tags are namespaced with
+// SYAC_TAG_FORMAT (default "{{.App}}/{{.Version}}"), and the first path is the
+// build context.
+func (c Context) ForComponent(comp Component) (Context, error) {
+	tmpl := os.Getenv("SYAC_TAG_FORMAT")
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultComponentTagFormat
+	}
+	if !strings.Contains(tmpl, ".App") {
+		return Context{}, fmt.Errorf("component %s: SYAC_TAG_FORMAT %q must reference {{.App}} so component tags don't collide", comp.Name, tmpl)
+	}
+	tf, err := newTagFormat(tmpl, comp.Name)
+	if err != nil {
+		return Context{}, fmt.Errorf("component %s: %w", comp.Name, err)
+	}
+
+	out := c
+	out.Component = comp.Name
+	out.ComponentPaths = comp.Paths
+	out.ApplicationName = comp.Name
+	out.TagFormat = tf
+	out.BuildContext = comp.Paths[0]
+	out.Dockerfile = path.Join(comp.Paths[0], "Dockerfile")
+	out.BumpCommits = nil
+	out.NextVersion = ""
+	out.NextRCVersion = ""
+	out.VersionFilesCommit = ""
+	out.ImageRef = out.resolveImageRef()
+	return out, nil
+}
+
+// AffectedComponents returns the components a pipeline should build:
+//   - tag pipeline → the component whose tag format matches CI_COMMIT_TAG
+//   - otherwise    → components with files changed since their latest tag
+//     (components without any tag yet are always affected)
+//
+// Without a GitLab client the change set is unknown, so every component is built.
+func AffectedComponents(ctx context.Context, client *gitlab.Client, c *Context, comps []Component, logger func(string, ...any)) []Component {
+	if c.IsTag {
+		for _, comp := range comps {
+			cc, err := c.ForComponent(comp)
+			if err != nil {
+				continue
+			}
+			if _, err := cc.TagFormat.Parse(c.Tag); err == nil {
+				return []Component{comp}
+			}
+		}
+		logger("[components] tag %q matches no component; nothing to build", c.Tag)
+		return nil
+	}
+
+	if client == nil || strings.TrimSpace(c.SHA) == "" {
+		logger("[components] change set unknown (no GitLab client or SHA); building all components")
+		return comps
+	}
+
+	var out []Component
+	for _, comp := range comps {
+		changed, err := componentChanged(ctx, client, c, comp)
+		if err != nil {
+			logger("[components] warn: %s: %v; treating as changed", comp.Name, err)
+			changed = true
+		}
+		if changed {
+			out = append(out, comp)
+		} else {
+			logger("[components] %s: no changes since last tag; skipping", comp.Name)
+		}
+	}
+	return out
+}
+
+func componentChanged(ctx context.Context, client *gitlab.Client, c *Context, comp Component) (bool, error) {
+	cc, err := c.ForComponent(comp)
+	if err != nil {
+		return false, err
+	}
+	latest, err := cc.tags(client).GetLatestTagCtx(ctx)
+	if errors.Is(err, gitlab.ErrNoTags) {
+		return true, nil // never released
+	}
+	if err != nil {
+		return false, err
+	}
+
+	cmp, err := client.Commits.CompareCtx(ctx, cc.TagFormat.Format(latest), c.SHA)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range cmp.Diffs {
+		if comp.Owns(d.NewPath) || comp.Owns(d.OldPath) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -1,6 +1,8 @@
 package runtime
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -25,6 +27,10 @@ type Context struct {
 	Sprint                   string
 	ApplicationName          string
 	MergeRequestTargetBranch string
+	Component                string   // monorepo component name (see SYAC_COMPONENTS)
+	ComponentPaths           []string // path filters owning the component
+	BuildContext             string   // docker build context override (components)
+	Dockerfile               string   // Dockerfile override (components)
 	ProjectID                string
 
 	// Derived booleans
@@ -33,21 +39,47 @@ type Context struct {
 	IsFeatureBranch     bool
 	FeatureBranchPrefix string
 	IsDefaultBranch     bool
+	IsMaintenanceBranch bool
 	DryRun              bool
 
+	// MaintenanceLine is the MAJOR.MINOR line of a maintenance branch build, or of
+	// the maintenance branch an MR targets. Nil everywhere else.
+	MaintenanceLine *version.Line
+
+	// Directives from the commit message (branch pushes only)
+	Directives Directives
+
 	// Proposed release metadata
 	// For feature branches, this is ALWAYS the short SHA.
 	FeatureTag string
 	ImageRef   string
 
 	// Version forecast metadata
-	BumpType      version.VersionType // Major | Minor | Patch
-	NextVersion   string              // e.g., "1.4.3" or "v1.4.3"
-	NextRCVersion string              // e.g., "1.4.3-rc.1" or "v1.4.3-<shortsha>"
+	BumpType      version.VersionType   // Major | Minor | Patch
+	BumpCommits   []BumpCommit          // commits that drove a Conventional Commits bump
+	BumpSelection *gitlab.BumpSelection // MR release-type selection, when the MR source was read
+	BumpLabels    gitlab.BumpLabels     // SYAC_BUMP_LABELS + SYAC_SYNC_BUMP_LABEL
+	TagFormat     version.TagFormat     // SYAC_TAG_FORMAT + SYAC_VERSION_SCHEME, e.g. "v{{.Version}}"
+	NextVersion   string                // tag name, e.g., "1.4.3" or "v1.4.3"
+	NextRCVersion string                // tag name, e.g., "1.4.3-rc.2" (next free RC number)
+
+	// ReleaseTag is the release tag on this commit, found on retries or created
+	// by the release step.
+	ReleaseTag string
+
+	// VersionFilesCommit is the SHA of the commit that synced SYAC_VERSION_FILES, if any.
+	VersionFilesCommit string
+
+	// MergedMR is the MR that landed this commit on a default/maintenance branch
+	// build (merge, squash or fast-forward), resolved lazily by mergedMR.
+	MergedMR       *gitlab.MergeRequest
+	mergedMRLooked bool
 }
 
 // LoadContext constructs a CI Context by reading GitLab CI/CD environment variables.
-func LoadContext() (Context, error) {
+// On branch pushes it also reads commit-message Directives, through client when
+// non-nil (see commitDirectives).
+func LoadContext(ctx context.Context, client *gitlab.Client) (Context, error) {
 	tag := os.Getenv("CI_COMMIT_TAG")
 	def := os.Getenv("CI_DEFAULT_BRANCH")
 
@@ -69,6 +101,18 @@ func LoadContext() (Context, error) {
 		effectiveRef != def &&
 		strings.HasPrefix(effectiveRef, featurePrefix)
 
+	// Maintenance branches (release/1.4) pin versions to their MAJOR.MINOR line.
+	// MRs into a maintenance branch forecast on the target's line.
+	maintRef := effectiveRef
+	if isMR {
+		maintRef = os.Getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME")
+	}
+	line, err := maintenanceLine(maintRef)
+	if err != nil {
+		return Context{}, err
+	}
+	isMaintenance := !isTag && !isMR && line != nil
+
 	// Ensure ShortSHA is populated (fallback if CI_COMMIT_SHORT_SHA is missing)
 	short := os.Getenv("CI_COMMIT_SHORT_SHA")
 	if short == "" {
@@ -88,7 +132,17 @@ func LoadContext() (Context, error) {
 		}
 	}
 
-	ctx := Context{
+	appName := resolveApplicationName()
+	tagFormat, err := newTagFormat(os.Getenv("SYAC_TAG_FORMAT"), appName)
+	if err != nil {
+		return Context{}, err
+	}
+	bumpLabels, err := bumpLabelsFromEnv()
+	if err != nil {
+		return Context{}, err
+	}
+
+	c := Context{
 		Source:                   os.Getenv("CI_PIPELINE_SOURCE"),
 		RefName:                  rawRef,
 		EffectiveRef:             effectiveRef,
@@ -106,34 +160,89 @@ func LoadContext() (Context, error) {
 		IsFeatureBranch:          isFeature,
 		FeatureBranchPrefix:      featurePrefix,
 		IsDefaultBranch:          rawRef != "" && rawRef == def,
+		IsMaintenanceBranch:      isMaintenance,
+		MaintenanceLine:          line,
 		ProjectID:                os.Getenv("CI_PROJECT_ID"),
-		ApplicationName:          resolveApplicationName(),
+		ApplicationName:          appName,
 		DryRun:                   os.Getenv("SYAC_DRY_RUN") == "true",
 		BumpType:                 bump,
+		TagFormat:                tagFormat,
+		BumpLabels:               bumpLabels,
+	}
+
+	// Branch pushes: Release-Type trailer and [syac ...] markers. MR and tag
+	// pipelines carry their own selection.
+	if !isMR && !isTag {
+		c.Directives = commitDirectives(ctx, client, c.SHA)
 	}
 
 	// Feature branches: only short SHA as tag.
-	if ctx.IsFeatureBranch {
-		ctx.FeatureTag = ctx.ShortSHA
-	}
-
-	// Build ImageRef:
-	//   - feature branch -> <image>/<app>:<shortsha>
-	//   - tag pipeline   -> <image>/<app>:<tag>
-	//   - default branch -> <image>/<app>:<default-branch>
-	if ctx.RegistryImage != "" && ctx.ApplicationName != "" {
-		base := fmt.Sprintf("%s/%s", ctx.RegistryImage, ctx.ApplicationName)
-		switch {
-		case ctx.IsFeatureBranch && ctx.FeatureTag != "":
-			ctx.ImageRef = fmt.Sprintf("%s:%s", base, ctx.FeatureTag)
-		case ctx.IsTag && ctx.Tag != "":
-			ctx.ImageRef = fmt.Sprintf("%s:%s", base, ctx.Tag)
-		case ctx.IsDefaultBranch && ctx.DefaultBranch != "":
-			ctx.ImageRef = fmt.Sprintf("%s:%s", base, ctx.DefaultBranch)
-		}
+	if c.IsFeatureBranch {
+		c.FeatureTag = c.ShortSHA
 	}
 
-	return ctx, nil
+	c.ImageRef = c.resolveImageRef()
+
+	return c, nil
+}
+
+// ImageVersion returns the docker tag for a version git tag: the version as
+// rendered by the scheme, without the tag format's literal text, so a
+// component tag "api/1.2.3" becomes "1.2.3" rather than "api-1.2.3" (the image
+// path already names the component). Tags outside the format are returned as is.
+func (c Context) ImageVersion(tag string) string {
+	v, err := c.TagFormat.Parse(strings.TrimSpace(tag))
+	if err != nil {
+		return tag
+	}
+	return c.TagFormat.Scheme().Format(v)
+}
+
+// resolveImageRef builds ImageRef:
+//   - feature branch -> <image>/<app>:<shortsha>
+//   - tag pipeline   -> <image>/<app>:<version> (see ImageVersion)
+//   - default branch -> <image>/<app>:<default-branch>
+func (c Context) resolveImageRef() string {
+	if c.RegistryImage == "" || c.ApplicationName == "" {
+		return ""
+	}
+	base := fmt.Sprintf("%s/%s", c.RegistryImage, c.ApplicationName)
+	switch {
+	case c.IsFeatureBranch && c.FeatureTag != "":
+		return fmt.Sprintf("%s:%s", base, c.FeatureTag)
+	case c.IsTag && c.Tag != "":
+		return fmt.Sprintf("%s:%s", base, c.ImageVersion(c.Tag))
+	case c.IsDefaultBranch && c.DefaultBranch != "":
+		return fmt.Sprintf("%s:%s", base, c.DefaultBranch)
+	}
+	return ""
+}
+
+// newTagFormat builds the tag format for app from a SYAC_TAG_FORMAT template and
+// the SYAC_VERSION_SCHEME versioning scheme (semver, calver, calver:<layout>).
+// SYAC_INITIAL_DEVELOPMENT=true enables SemVer's 0.x mode.
+func newTagFormat(tmpl, app string) (version.TagFormat, error) {
+	scheme, err := version.ParseScheme(os.Getenv("SYAC_VERSION_SCHEME"))
+	if err != nil {
+		return version.TagFormat{}, fmt.Errorf("SYAC_VERSION_SCHEME: %w", err)
+	}
+	if _, ok := scheme.(version.SemVerScheme); ok && os.Getenv("SYAC_INITIAL_DEVELOPMENT") == "true" {
+		// Opt-in 0.x mode: Major bumps minor, Minor bumps patch while below 1.0.0.
+		scheme = version.SemVerScheme{InitialDevelopment: true}
+	}
+	tf, err := version.NewTagFormat(tmpl, app)
+	if err != nil {
+		return version.TagFormat{}, fmt.Errorf("SYAC_TAG_FORMAT: %w", err)
+	}
+	return tf.WithScheme(scheme), nil
+}
+
+// rcIdentifier is the pre-release identifier used for numbered RCs (SYAC_RC_ID, default "rc").
+func rcIdentifier() string {
+	if v := strings.TrimSpace(os.Getenv("SYAC_RC_ID")); v != "" {
+		return v
+	}
+	return "rc"
 }
 
 // resolveApplicationName picks the application name:
@@ -151,9 +260,17 @@ func resolveApplicationName() string {
 	return ""
 }
 
+// maxBumpCommitsShown caps how many driving commits PrintSummary lists.
+const maxBumpCommitsShown = 5
+
 // PrintSummary emits a scannable CI/CD context report with logical sections.
 // NOTE: pointer receiver so computed fields (e.g., NextRCVersion) persist.
-func (c *Context) PrintSummary(client *gitlab.Client) {
+// It returns an error when the version forecast fails (e.g. tags could not be
+// listed) or an ErrGuardrail error when the bump or forecast violates a
+// guardrail; callers should fail the job.
+func (c *Context) PrintSummary(ctx context.Context, client *gitlab.Client) error {
+	var violations, failures []error
+
 	fmt.Println("CI/CD Environment Summary")
 	fmt.Println("--------------------------")
 
@@ -188,6 +305,12 @@ func (c *Context) PrintSummary(client *gitlab.Client) {
 		fmt.Printf("  Merge Request IID     : %s\n", formatOrNone(c.MRID))
 		fmt.Printf("  Target Branch         : %s\n", formatOrNone(c.MergeRequestTargetBranch))
 		fmt.Println()
+	} else if mr := c.mergedMR(ctx, client); mr != nil {
+		fmt.Println("Merged Merge Request")
+		fmt.Printf("  Merge Request IID     : !%d\n", mr.IID)
+		fmt.Printf("  Title                 : %s\n", mr.Title)
+		fmt.Printf("  Source Branch         : %s\n", formatOrNone(mr.SourceBranch))
+		fmt.Println()
 	}
 
 	// ── Project / Image ─────────────────────────────────────────────────────────
@@ -199,48 +322,94 @@ func (c *Context) PrintSummary(client *gitlab.Client) {
 		fmt.Printf("  Image Ref             : %s\n", c.ImageRef)
 	}
 	fmt.Printf("  Application Name      : %s\n", formatOrNone(c.ApplicationName))
+	if c.Component != "" {
+		fmt.Printf("  Component             : %s (%s)\n", c.Component, strings.Join(c.ComponentPaths, ", "))
+	}
 	fmt.Println()
 
 	// ── Derived Flags ───────────────────────────────────────────────────────────
 	fmt.Println("Derived")
 	fmt.Printf("  Is Merge Request      : %s\n", emoji(c.IsMergeRequest))
 	fmt.Printf("  Is Default Branch     : %s\n", emoji(c.IsDefaultBranch))
+	fmt.Printf("  Is Maintenance Branch : %s\n", emoji(c.IsMaintenanceBranch))
+	if c.MaintenanceLine != nil {
+		fmt.Printf("  Maintenance Line      : %s.x\n", c.MaintenanceLine)
+	}
 	fmt.Printf("  Is Feature Branch     : %s\n", emoji(c.IsFeatureBranch))
 	fmt.Printf("  Is Tag Build          : %s\n", emoji(c.IsTag))
 	fmt.Printf("  Dry Run Mode          : %s\n", emoji(c.DryRun))
-	// If this is an MR and no explicit SYAC_BUMP was provided, try to pull from the MR.
+	if d := c.Directives.String(); d != "" {
+		fmt.Printf("  Commit Directives     : %s\n", d)
+	}
+	// Walk the configured bump sources (SYAC_BUMP, MR checkbox, commits).
 	// Do this BEFORE printing the bump type so we only print once.
-	if src := c.ResolveBumpFromMR(client); src != "" {
+	src := c.ResolveBump(ctx, client)
+	if note := c.applyBumpPolicy(); note != "" {
+		src = strings.TrimPrefix(src+"; "+note, "; ")
+	}
+	if src != "" {
 		fmt.Printf("  Bump Type             : %s (from %s)\n", c.BumpType.String(), src)
 	} else {
 		fmt.Printf("  Bump Type             : %s\n", c.BumpType.String())
 	}
+	for i, bc := range c.BumpCommits {
+		if i == maxBumpCommitsShown {
+			fmt.Printf("                          ... and %d more\n", len(c.BumpCommits)-i)
+			break
+		}
+		fmt.Printf("  Bump Commit           : %s %s\n", bc.ShortID, bc.Title)
+	}
+	if sel := c.BumpSelection; sel != nil {
+		for _, conflict := range sel.Conflicts {
+			fmt.Printf("  Bump Conflict         : ⚠️ %s\n", conflict)
+		}
+	}
+	if err := c.checkBumpConflict(); err != nil {
+		violations = append(violations, err)
+	}
 	fmt.Println()
 
 	// ── Tags + Forecast ─────────────────────────────────────────────────────────
 	fmt.Println("Tags")
+	fmt.Printf("  Tag Format            : %s\n", c.TagFormat.String())
+	fmt.Printf("  Version Scheme        : %s\n", c.TagFormat.Scheme().Name())
 
 	var latestTagStr string
+	// SYAC_MAX_BUMP limits what the release applies, known once the
+	// forecast has the current version.
+	bump := c.BumpType
 
 	if client == nil {
 		fmt.Println("  Status                : Skipped (no GitLab client)")
+	} else if tag := c.releasedTag(ctx, client); tag != "" {
+		// Retried pipeline on a commit that was already released: keep its
+		// version instead of forecasting past our own tag.
+		c.ReleaseTag = tag
+		c.NextVersion = tag
+		fmt.Printf("  Released As           : %s (commit already tagged)\n", tag)
 	} else {
 		// Use Tags service as the single source of truth.
 		// This already defaults to 0.0.0 when no valid semver tags exist.
-		current, next, err := client.Tags.GetNextVersion(c.BumpType)
+		current, next, err := c.tags(client).GetNextVersionCtx(ctx, c.BumpType)
 		if err != nil {
+			// Never forecast from 0.0.0 because the tag listing failed.
 			fmt.Printf("  Status                : Error (%v)\n", err)
+			failures = append(failures, fmt.Errorf("version forecast: %w", err))
 		} else {
-			latestTagStr = current.String()
-			c.NextVersion = next.String()
+			latestTagStr = c.TagFormat.Format(current)
+			c.NextVersion = c.TagFormat.Format(next)
 
 			fmt.Printf("  Latest Tag            : %s\n", latestTagStr)
 			fmt.Printf("  Next Version          : %s\n", c.NextVersion)
+			bump = c.effectiveBump(current)
+			if gerr := c.checkVersionGuardrail(current, next); gerr != nil {
+				violations = append(violations, gerr)
+			}
 
-			// Compute RC tag (semver-next + short SHA) for MR/dev flows.
-			if (c.IsMergeRequest || c.IsDefaultBranch) && c.ShortSHA != "" {
-				if rc, rerr := version.ForecastNextRC(latestTagStr, c.BumpType, c.ShortSHA); rerr == nil {
-					c.NextRCVersion = rc
+			// Allocate the next numbered RC (<next>-rc.N+1) for MR/dev/maintenance flows.
+			if c.IsMergeRequest || c.IsDefaultBranch || c.IsMaintenanceBranch {
+				if rc, rerr := c.tags(client).GetNextPreReleaseCtx(ctx, next, rcIdentifier()); rerr == nil {
+					c.NextRCVersion = c.TagFormat.Format(rc)
 					fmt.Printf("  Next RC Version       : %s\n", c.NextRCVersion)
 				} else {
 					fmt.Printf("  Next RC Version       : (error) %v\n", rerr)
@@ -248,7 +417,97 @@ func (c *Context) PrintSummary(client *gitlab.Client) {
 			}
 		}
 	}
+	if err := c.checkBumpGuardrail(bump); err != nil {
+		violations = append(violations, err)
+	}
 	fmt.Println()
+
+	// ── Guardrails (conditional) ────────────────────────────────────────────────
+	if len(violations) > 0 {
+		fmt.Println("Guardrails")
+		for _, v := range violations {
+			fmt.Printf("  Violation             : ❌ %v\n", v)
+		}
+		fmt.Println()
+	}
+	return errors.Join(append(failures, violations...)...)
+}
+
+// tags returns the Tags service bound to this context's tag format and, on
+// maintenance branches (or MRs into them), to the maintenance line.
+func (c *Context) tags(client *gitlab.Client) gitlab.TagsService {
+	tags := client.Tags.WithFormat(c.TagFormat)
+	if c.MaintenanceLine != nil {
+		tags = tags.WithLine(*c.MaintenanceLine)
+	}
+	return tags
+}
+
+// mrs returns the MergeRequests service bound to this context's release-type labels.
+func (c *Context) mrs(client *gitlab.Client) gitlab.MergeRequestsService {
+	return client.MergeRequests.WithBumpLabels(c.BumpLabels)
+}
+
+// isBranchPush reports a push build of the default or a maintenance branch,
+// i.e. a commit that landed there (not an MR or tag pipeline).
+func (c *Context) isBranchPush() bool {
+	return (c.IsDefaultBranch || c.IsMaintenanceBranch) && !c.IsMergeRequest && !c.IsTag && c.SHA != ""
+}
+
+// mergedMR resolves (once) the MR that landed this commit on branch push builds.
+// Returns nil without a client, outside branch pushes, or when no MR is found.
+func (c *Context) mergedMR(ctx context.Context, client *gitlab.Client) *gitlab.MergeRequest {
+	if c.mergedMRLooked || client == nil || !c.isBranchPush() {
+		return c.MergedMR
+	}
+	c.mergedMRLooked = true
+	mr, err := client.MergeRequests.GetMergeRequestForCommitCtx(ctx, c.SHA)
+	if err != nil {
+		fmt.Printf("[mr] no merged MR found for %s: %v\n", c.ShortSHA, err)
+		return nil
+	}
+	c.MergedMR = &mr
+	return c.MergedMR
+}
+
+// releasedTag returns the release tag already pointing at this commit, or at
+// the version-sync commit pushed on top of it, on default/maintenance branch
+// builds, or "".
+func (c *Context) releasedTag(ctx context.Context, client *gitlab.Client) string {
+	if !c.isBranchPush() {
+		return ""
+	}
+	for _, sha := range []string{c.SHA, c.versionFilesCommit(ctx, client)} {
+		if sha == "" {
+			continue
+		}
+		if tag, _, err := c.tags(client).GetReleaseTagForCommitCtx(ctx, sha); err == nil {
+			return tag.Name
+		}
+	}
+	return ""
+}
+
+// versionFilesCommit returns this build's version-sync commit: the one made by
+// this run, or the one an earlier attempt of the pipeline left for this
+// component among the sync commits between SHA and the branch head (recorded
+// in VersionFilesCommit). Another component's sync commit never counts.
+// Returns "" when SYAC_VERSION_FILES is not configured or there is none.
+func (c *Context) versionFilesCommit(ctx context.Context, client *gitlab.Client) string {
+	if c.VersionFilesCommit != "" || strings.TrimSpace(os.Getenv("SYAC_VERSION_FILES")) == "" {
+		return c.VersionFilesCommit
+	}
+	synced, foreign, err := versionFilesCommits(ctx, client, c.RefName, c.SHA)
+	if err != nil || foreign.ID != "" {
+		return ""
+	}
+	for _, s := range synced {
+		if component, _ := isVersionFilesCommit(s); component == c.Component {
+			c.VersionFilesCommit = s.ID
+			break
+		}
+	}
+	return c.VersionFilesCommit
 }
 
 func (c Context) describeContext() string {
@@ -259,6 +518,8 @@ func (c Context) describeContext() string {
 		return fmt.Sprintf("Tag push (%s)", c.Tag)
 	case c.IsDefaultBranch:
 		return fmt.Sprintf("Push to default branch (%s)", c.RefName)
+	case c.IsMaintenanceBranch:
+		return fmt.Sprintf("Push to maintenance branch (%s → %s.x)", c.RefName, c.MaintenanceLine)
 	case c.IsFeatureBranch:
 		return fmt.Sprintf("Development Branch (%s)", c.EffectiveRef)
 	}
@@ -267,31 +528,3 @@ func (c Context) describeContext() string {
 	}
 	return fmt.Sprintf("Pipeline source: %s", c.Source)
 }
-
-// ResolveBumpFromMR overrides BumpType from the MR checkbox selection when possible.
-// - Only applies on merge request pipelines with a valid MRID
-// - Respects env override: if SYAC_BUMP is set, we do nothing.
-// Returns a short string describing where the bump came from, or "" if unchanged.
-func (c *Context) ResolveBumpFromMR(client *gitlab.Client) string {
-	// If user explicitly set SYAC_BUMP, don't second-guess them.
-	if strings.TrimSpace(os.Getenv("SYAC_BUMP")) != "" {
-		return ""
-	}
-	// Need an MR and a client to query.
-	if !c.IsMergeRequest || strings.TrimSpace(c.MRID) == "" || client == nil {
-		return ""
-	}
-
-	bump, err := client.MergeRequests.GetVersionBump(c.MRID)
-	if err != nil {
-		// Keep existing BumpType on error.
-		return ""
-	}
-
-	// Only log a change if it actually changes the value.
-	if bump != c.BumpType {
-		c.BumpType = bump
-		return "MR selection"
-	}
-	return "MR selection"
-}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RunContext returns the context bounding a whole syac run. It is cancelled on
+// SIGINT/SIGTERM (GitLab sends SIGTERM when a job is cancelled or times out)
+// and, when a deadline is configured, when it expires:
+//   - SYAC_TIMEOUT, a Go duration (e.g. "20m"); "0" disables the deadline
+//   - otherwise CI_JOB_TIMEOUT (seconds), as exported by GitLab CI
+func RunContext() (context.Context, context.CancelFunc, error) {
+	timeout, err := runTimeout()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() { cancel(); stop() }, nil
+}
+
+func runTimeout() (time.Duration, error) {
+	if raw := strings.TrimSpace(os.Getenv("SYAC_TIMEOUT")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid SYAC_TIMEOUT %q: want a duration like \"20m\"", raw)
+		}
+		return d, nil
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(os.Getenv("CI_JOB_TIMEOUT"))); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+)
+
+// Directives are per-commit instructions read from the commit message, for
+// pushes that have no MR to carry a selection:
+//
+//	Release-Type: minor   git trailer: patch, minor or major (see ResolveBump)
+//	[syac skip-push]      build the image but don't push it
+//	[syac no-release]     don't tag, RC-tag, release or sync version files
+type Directives struct {
+	Bump      version.VersionType // "" without a Release-Type trailer
+	SkipPush  bool
+	NoRelease bool
+}
+
+var (
+	releaseTypeTrailerRe = regexp.MustCompile(`(?i)^Release-Type:\s*(\S+)\s*$`)
+	skipPushMarkerRe     = regexp.MustCompile(`(?i)\[syac skip-push\]`)
+	noReleaseMarkerRe    = regexp.MustCompile(`(?i)\[syac no-release\]`)
+)
+
+// ParseDirectives reads directives from a full commit message. Trailers are
+// only recognized in the last paragraph, as git does; markers anywhere.
+// An unknown Release-Type value is an error; the markers still apply.
+func ParseDirectives(message string) (Directives, error) {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	d := Directives{
+		SkipPush:  skipPushMarkerRe.MatchString(message),
+		NoRelease: noReleaseMarkerRe.MatchString(message),
+	}
+
+	paragraphs := strings.Split(message, "\n\n")
+	if len(paragraphs) < 2 {
+		return d, nil // subject only: no trailers
+	}
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		m := releaseTypeTrailerRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		vt, err := version.ParseVersionType(m[1])
+		if err != nil {
+			return d, fmt.Errorf("Release-Type trailer: %w", err)
+		}
+		d.Bump = vt
+	}
+	return d, nil
+}
+
+// String lists the active directives, e.g. "Release-Type: Minor, skip-push".
+func (d Directives) String() string {
+	var out []string
+	if d.Bump != "" {
+		out = append(out, "Release-Type: "+d.Bump.String())
+	}
+	if d.SkipPush {
+		out = append(out, "skip-push")
+	}
+	if d.NoRelease {
+		out = append(out, "no-release")
+	}
+	return strings.Join(out, ", ")
+}
+
+// commitDirectives reads the directives of sha's commit message from GitLab,
+// falling back to CI_COMMIT_MESSAGE without a client or when the lookup fails.
+// Problems are logged, never fatal.
+func commitDirectives(ctx context.Context, client *gitlab.Client, sha string) Directives {
+	message := os.Getenv("CI_COMMIT_MESSAGE")
+	if client != nil && strings.TrimSpace(sha) != "" {
+		if commit, err := client.Commits.GetCommitCtx(ctx, sha); err == nil {
+			message = commit.Message
+		} else {
+			fmt.Printf("[directives] warn: commit lookup failed, using CI_COMMIT_MESSAGE: %v\n", err)
+		}
+	}
+	d, err := ParseDirectives(message)
+	if err != nil {
+		fmt.Printf("[directives] warn: ignoring %v\n", err)
+	}
+	return d
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestParseDirectives(t *testing.T) {
+	for _, tc := range []struct {
+		message string
+		want    Directives
+		wantErr bool
+	}{
+		{"fix: typo", Directives{}, false},
+		{"fix: typo\n\nRelease-Type: minor", Directives{Bump: version.Minor}, false},
+		{"hotfix [SYAC skip-push]\n\nbody\n\nrelease-type: Major\nSigned-off-by: a <a@b.c>", Directives{Bump: version.Major, SkipPush: true}, false},
+		{"Release-Type: minor", Directives{}, false},                          // subject, not a trailer
+		{"chore: x\n\nRelease-Type: minor\n\nmore text", Directives{}, false}, // not the last paragraph
+		{"chore: docs [syac no-release]\r\n\r\nRelease-Type: huge", Directives{NoRelease: true}, true},
+	} {
+		got, err := ParseDirectives(tc.message)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("ParseDirectives(%q) = %+v, %v; want %+v (err %v)", tc.message, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestLoadContextReadsCommitDirectives(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "hotfix: patch the thing [syac no-release]\n\nRelease-Type: minor\n"})
+	for k, v := range map[string]string{
+		"CI_COMMIT_SHA":        head.ID,
+		"CI_COMMIT_BRANCH":     "main",
+		"CI_COMMIT_REF_NAME":   "main",
+		"CI_DEFAULT_BRANCH":    "main",
+		"CI_PIPELINE_SOURCE":   "push",
+		"CI_COMMIT_MESSAGE":    "stale message",
+		"CI_MERGE_REQUEST_IID": "",
+		"CI_COMMIT_TAG":        "",
+		"SYAC_BUMP":            "",
+		"SYAC_BUMP_SOURCES":    "",
+		"SYAC_AUTO_RELEASE":    "true",
+		"SYAC_CREATE_RC_TAG":   "true",
+		"SYAC_VERSION_FILES":   "VERSION",
+	} {
+		t.Setenv(k, v)
+	}
+
+	c, err := LoadContext(context.Background(), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Directives.Bump != version.Minor || !c.Directives.NoRelease {
+		t.Fatalf("directives = %+v; want Minor + no-release", c.Directives)
+	}
+	// The trailer is one of the bump sources, applied by ResolveBump only.
+	if c.BumpType != version.Patch {
+		t.Fatalf("bump after LoadContext = %s, want Patch until ResolveBump", c.BumpType)
+	}
+	if src := c.ResolveBump(context.Background(), srv.Client()); src != "Release-Type trailer" || c.BumpType != version.Minor {
+		t.Fatalf("ResolveBump = %q, bump = %s; want Minor from the trailer", src, c.BumpType)
+	}
+	c.NextVersion, c.NextRCVersion = "1.1.0", "1.1.0-rc.1"
+	if ShouldRelease(&c) || ShouldCreateRCTag(&c) || ShouldSyncVersionFiles(&c) {
+		t.Fatal("[syac no-release] commit must not release, RC-tag or sync version files")
+	}
+
+	// Without a client the CI variable is used.
+	t.Setenv("CI_COMMIT_MESSAGE", "fix: x [syac skip-push]")
+	if c, _ := LoadContext(context.Background(), nil); !c.Directives.SkipPush || c.BumpType != version.Patch {
+		t.Fatalf("directives = %+v, bump = %s; want skip-push from CI_COMMIT_MESSAGE", c.Directives, c.BumpType)
+	}
+}
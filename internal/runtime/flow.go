@@ -3,11 +3,12 @@ package runtime
 type Flow string
 
 const (
-	FlowAuto    Flow = "auto"
-	FlowFeature Flow = "feature"
-	FlowMR      Flow = "mr"
-	FlowDefault Flow = "default"
-	FlowRelease Flow = "release"
+	FlowAuto        Flow = "auto"
+	FlowFeature     Flow = "feature"
+	FlowMR          Flow = "mr"
+	FlowDefault     Flow = "default"
+	FlowMaintenance Flow = "maintenance"
+	FlowRelease     Flow = "release"
 )
 
 func ResolveFlow(ctx Context, forced Flow) Flow {
@@ -21,6 +22,8 @@ func ResolveFlow(ctx Context, forced Flow) Flow {
 		return FlowMR
 	case ctx.IsDefaultBranch:
 		return FlowDefault
+	case ctx.IsMaintenanceBranch:
+		return FlowMaintenance
 	case ctx.IsFeatureBranch:
 		return FlowFeature
 	default:
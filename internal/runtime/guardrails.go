@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"syac/internal/version"
+)
+
+// ErrGuardrail marks a version decision syac refuses to act on.
+var ErrGuardrail = errors.New("version guardrail")
+
+// guardrailBranch is the branch a bump lands on: the MR target, or the pushed branch.
+func (c *Context) guardrailBranch() string {
+	if c.IsMergeRequest {
+		return strings.TrimSpace(c.MergeRequestTargetBranch)
+	}
+	return firstNonEmpty(c.EffectiveRef, c.RefName)
+}
+
+// maxBumpFor reads SYAC_MAX_BUMP, a comma-separated list of <branch-glob>=<level>
+// (e.g. "main=minor,release/*=patch"), and returns the limit of the first
+// pattern matching branch. ok is false when no limit applies.
+func maxBumpFor(branch string) (limit version.VersionType, ok bool, err error) {
+	raw := strings.TrimSpace(os.Getenv("SYAC_MAX_BUMP"))
+	if raw == "" || branch == "" {
+		return "", false, nil
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, level, found := strings.Cut(entry, "=")
+		if !found {
+			return "", false, fmt.Errorf("SYAC_MAX_BUMP: entry %q must be <branch>=<level>", entry)
+		}
+		vt, perr := version.ParseVersionType(strings.TrimSpace(level))
+		if perr != nil {
+			return "", false, fmt.Errorf("SYAC_MAX_BUMP: %w", perr)
+		}
+		matched, merr := path.Match(strings.TrimSpace(pattern), branch)
+		if merr != nil {
+			return "", false, fmt.Errorf("SYAC_MAX_BUMP: bad pattern %q: %w", pattern, merr)
+		}
+		if matched {
+			return vt, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// effectiveBump is the bump a release from current actually applies: BumpType
+// after the bump policy (see clampBump), shifted down in SemVer's 0.x mode.
+func (c *Context) effectiveBump(current version.Version) version.VersionType {
+	bump := c.clampBump(c.BumpType)
+	if s, ok := c.TagFormat.Scheme().(version.SemVerScheme); ok {
+		bump = s.EffectiveBump(current, bump)
+	}
+	return bump
+}
+
+// checkBumpGuardrail refuses bumps above the target branch's SYAC_MAX_BUMP.
+// Pass the effective bump when the current version is known, so a bump that
+// the 0.x mode or a maintenance line reduces isn't refused.
+func (c *Context) checkBumpGuardrail(bump version.VersionType) error {
+	branch := c.guardrailBranch()
+	limit, ok, err := maxBumpFor(branch)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGuardrail, err)
+	}
+	if ok && bump.Rank() > limit.Rank() {
+		return fmt.Errorf("%w: %s bump exceeds the %s limit for branch %q", ErrGuardrail, bump, limit, branch)
+	}
+	return nil
+}
+
+// checkBumpConflict refuses an ambiguous release-type selection on MR
+// pipelines when SYAC_FAIL_ON_BUMP_CONFLICT=true, so the MR can't go green
+// until exactly one box is ticked. Merged MRs only warn.
+func (c *Context) checkBumpConflict() error {
+	sel := c.BumpSelection
+	if sel == nil || !sel.Ambiguous() || !c.IsMergeRequest || os.Getenv("SYAC_FAIL_ON_BUMP_CONFLICT") != "true" {
+		return nil
+	}
+	return fmt.Errorf("%w: ambiguous release-type selection on MR !%s: %s",
+		ErrGuardrail, c.MRID, strings.Join(sel.Conflicts, "; "))
+}
+
+// checkVersionGuardrail refuses a next version that is not strictly greater than
+// the highest existing tag of the stream (e.g. a CalVer clock going backwards).
+func (c *Context) checkVersionGuardrail(current, next version.Version) error {
+	if c.TagFormat.Scheme().Compare(next, current) <= 0 {
+		return fmt.Errorf("%w: next version %s is not greater than existing tag %s",
+			ErrGuardrail, c.TagFormat.Format(next), c.TagFormat.Format(current))
+	}
+	return nil
+}
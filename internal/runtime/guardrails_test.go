@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestMaxBumpFor(t *testing.T) {
+	for _, tc := range []struct {
+		env, branch string
+		want        version.VersionType // "" for no limit
+		wantErr     bool
+	}{
+		{"", "main", "", false},
+		{"main=minor,release/*=patch", "main", version.Minor, false},
+		{"main=minor,release/*=patch", "release/1.4", version.Patch, false},
+		{"main=minor,release/*=patch", "release/1.4/x", "", false}, // * doesn't cross "/"
+		{"main=minor,release/*=patch", "dev", "", false},
+		{"release/*=patch, *=minor", "release/2.0", version.Patch, false}, // first match wins
+		{" , *=major ,", "dev", version.Major, false},
+		{"main", "main", "", true},        // missing level
+		{"main=huge", "main", "", true},   // unknown level
+		{"[main=minor", "main", "", true}, // bad glob
+		{"main=minor", "", "", false},     // no branch, no limit
+	} {
+		t.Setenv("SYAC_MAX_BUMP", tc.env)
+		limit, ok, err := maxBumpFor(tc.branch)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("maxBumpFor(%q) with %q: err = %v, wantErr %v", tc.branch, tc.env, err, tc.wantErr)
+			continue
+		}
+		if ok != (tc.want != "") || limit != tc.want {
+			t.Errorf("maxBumpFor(%q) with %q = %s, %v; want %q", tc.branch, tc.env, limit, ok, tc.want)
+		}
+	}
+}
+
+func TestCheckBumpGuardrail(t *testing.T) {
+	t.Setenv("SYAC_MAX_BUMP", "main=minor")
+
+	mr := &Context{IsMergeRequest: true, MergeRequestTargetBranch: "main", EffectiveRef: "feature-x"}
+	if err := mr.checkBumpGuardrail(version.Major); !errors.Is(err, ErrGuardrail) {
+		t.Fatalf("Major into main: err = %v, want ErrGuardrail", err)
+	}
+	if err := mr.checkBumpGuardrail(version.Minor); err != nil {
+		t.Fatalf("Minor into main: err = %v", err)
+	}
+	push := &Context{EffectiveRef: "dev"}
+	if err := push.checkBumpGuardrail(version.Major); err != nil {
+		t.Fatalf("Major on unlimited branch: err = %v", err)
+	}
+
+	t.Setenv("SYAC_MAX_BUMP", "main")
+	if err := mr.checkBumpGuardrail(version.Patch); !errors.Is(err, ErrGuardrail) {
+		t.Fatalf("malformed SYAC_MAX_BUMP: err = %v, want ErrGuardrail", err)
+	}
+}
+
+func TestCheckBumpGuardrailUsesEffectiveBump(t *testing.T) {
+	zeroX := version.SemVerScheme{InitialDevelopment: true}
+	line := &version.Line{Major: 1, Minor: 4}
+	for _, tc := range []struct {
+		name    string
+		scheme  version.Scheme
+		line    *version.Line
+		bump    version.VersionType
+		current version.Version
+		want    version.VersionType
+	}{
+		{"0.x shifts Major", zeroX, nil, version.Major, version.Version{Minor: 3}, version.Minor},
+		{"0.x shifts Minor", zeroX, nil, version.Minor, version.Version{Minor: 3}, version.Patch},
+		{"0.x mode past 1.0", zeroX, nil, version.Major, version.Version{Major: 1}, version.Major},
+		{"default semver", version.SemVerScheme{}, nil, version.Major, version.Version{Minor: 3}, version.Major},
+		{"maintenance line", version.SemVerScheme{}, line, version.Minor, version.Version{Major: 1, Minor: 4, Patch: 2}, version.Patch},
+	} {
+		format, err := version.NewTagFormat("", "app")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &Context{TagFormat: format.WithScheme(tc.scheme), MaintenanceLine: tc.line, BumpType: tc.bump}
+		if got := c.effectiveBump(tc.current); got != tc.want {
+			t.Errorf("%s: effectiveBump = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	// End to end: a declared Major on a 0.x project only releases a minor,
+	// so main=minor lets it through; past 1.0.0 the same MR is refused.
+	t.Setenv("SYAC_MAX_BUMP", "main=minor")
+	t.Setenv("SYAC_INITIAL_DEVELOPMENT", "true")
+	t.Setenv("SYAC_VERSION_SCHEME", "")
+	t.Setenv("SYAC_BUMP", "major")
+	t.Setenv("SYAC_BUMP_SOURCES", "")
+	for _, tc := range []struct {
+		latest  string
+		refused bool
+	}{
+		{"0.3.0", false},
+		{"1.0.0", true},
+	} {
+		srv := gitlabtest.NewServer(t)
+		head := srv.AddCommit(gitlab.Commit{Message: "feat!: rework"})
+		srv.AddTag(tc.latest, head.ID)
+		srv.AddMergeRequest(gitlab.MergeRequest{IID: 3}, "")
+		format, err := newTagFormat("", "app")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := Context{IsMergeRequest: true, MRID: "3", MergeRequestTargetBranch: "main", SHA: head.ID, ShortSHA: head.ShortID, TagFormat: format}
+		err = c.PrintSummary(context.Background(), srv.Client())
+		if errors.Is(err, ErrGuardrail) != tc.refused {
+			t.Errorf("Major from %s into main=minor: err = %v, refused want %v", tc.latest, err, tc.refused)
+		}
+	}
+}
+
+func TestCheckVersionGuardrail(t *testing.T) {
+	semver, err := version.NewTagFormat("", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calver, err := version.ParseScheme("calver:YYYY.MM.MICRO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := func(s string) version.Version {
+		parsed, err := version.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	for _, tc := range []struct {
+		name          string
+		format        version.TagFormat
+		current, next string
+		wantErr       bool
+	}{
+		{"semver increase", semver, "1.2.3", "1.2.4", false},
+		{"semver equal", semver, "1.2.3", "1.2.3", true},
+		{"semver decrease", semver, "1.2.3", "1.1.9", true},
+		{"calver new month", semver.WithScheme(calver), "2026.9.4", "2026.10.0", false},
+		{"calver clock went back", semver.WithScheme(calver), "2026.10.2", "2026.9.0", true},
+		{"calver same", semver.WithScheme(calver), "2026.10.2", "2026.10.2", true},
+	} {
+		c := &Context{TagFormat: tc.format}
+		err := c.checkVersionGuardrail(v(tc.current), v(tc.next))
+		if (err != nil) != tc.wantErr || (err != nil && !errors.Is(err, ErrGuardrail)) {
+			t.Errorf("%s: checkVersionGuardrail(%s, %s) = %v, wantErr %v", tc.name, tc.current, tc.next, err, tc.wantErr)
+		}
+	}
+}
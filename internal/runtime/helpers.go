@@ -1,10 +1,14 @@
 package runtime
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 
+	"syac/internal/version"
 	"syac/pkg/gitlab"
 )
 
@@ -31,7 +35,7 @@ func ShouldUpdateMRDescription(c *Context) bool {
 
 // UpsertMRDescriptionIfNeeded is best-effort and never fails the pipeline.
 // It inserts the SYAC release-type block into the MR description if missing.
-func UpsertMRDescriptionIfNeeded(client *gitlab.Client, c *Context, logger func(string, ...any)) {
+func UpsertMRDescriptionIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) {
 	if client == nil || c == nil || !ShouldUpdateMRDescription(c) {
 		return
 	}
@@ -43,7 +47,7 @@ func UpsertMRDescriptionIfNeeded(client *gitlab.Client, c *Context, logger func(
 	// Prefer known MR IID; otherwise try to resolve by commit SHA.
 	mrID := strings.TrimSpace(c.MRID)
 	if mrID == "" {
-		if mr, err := client.MergeRequests.GetMergeRequestForCommit(c.SHA); err == nil {
+		if mr, err := client.MergeRequests.GetMergeRequestForCommitCtx(ctx, c.SHA); err == nil {
 			mrID = strconv.Itoa(mr.IID)
 		} else {
 			logger("[mr] warn: no MRID and lookup by commit failed: %v", err)
@@ -51,9 +55,165 @@ func UpsertMRDescriptionIfNeeded(client *gitlab.Client, c *Context, logger func(
 		}
 	}
 
-	if err := client.MergeRequests.InsertReleaseTypeInDescription(mrID); err != nil {
+	if err := c.mrs(client).InsertReleaseTypeInDescriptionCtx(ctx, mrID); err != nil {
 		logger("[mr] warn: insert description block failed: %v", err) // never fail pipeline
 		return
 	}
 	logger("[mr] inserted SYAC release-type block into MR description on !%s", mrID)
 }
+
+// ShouldUpsertMRComment gates the SYAC release-type note on the MR.
+// Conditions:
+//   - Must be an MR pipeline with a valid MR IID
+//   - Allow opt-out via SYAC_MR_COMMENT=false
+func ShouldUpsertMRComment(c *Context) bool {
+	if c == nil || !c.IsMergeRequest || strings.TrimSpace(c.MRID) == "" {
+		return false
+	}
+	return os.Getenv("SYAC_MR_COMMENT") != "false"
+}
+
+// BuildResult is what one application (or monorepo component) build leaves
+// for the SYAC note: its context and the image refs it pushed.
+type BuildResult struct {
+	Context Context
+	Pushed  []string
+}
+
+// UpsertMRCommentIfNeeded is best-effort and never fails the pipeline.
+// It creates or refreshes the SYAC release-type note with the version each
+// choice would release and the image refs this pipeline pushed. A monorepo
+// pipeline passes every component build so they share one note instead of
+// overwriting each other's. Builds whose forecast fails (tag lookup) are left
+// out; with none left the note falls back to placeholders.
+func UpsertMRCommentIfNeeded(ctx context.Context, client *gitlab.Client, builds []BuildResult, logger func(string, ...any)) {
+	if client == nil || len(builds) == 0 || !ShouldUpsertMRComment(&builds[0].Context) {
+		return
+	}
+	c := &builds[0].Context
+	mrID := strings.TrimSpace(c.MRID)
+
+	var forecasts []*gitlab.ReleaseForecast
+	for i := range builds {
+		b := &builds[i]
+		forecast, err := b.Context.releaseForecast(ctx, client)
+		if err != nil {
+			logger("[mr] warn: version forecast for note failed: %v", err)
+			continue
+		}
+		forecast.Images = b.Pushed
+		forecasts = append(forecasts, forecast)
+	}
+	if c.DryRun {
+		logger("[mr] dry-run: would upsert SYAC release-type note on !%s", mrID)
+		return
+	}
+
+	if err := c.mrs(client).UpsertMergeRequestCommentCtx(ctx, mrID, forecasts...); err != nil {
+		logger("[mr] warn: upsert release-type note failed: %v", err) // never fail pipeline
+		return
+	}
+	logger("[mr] refreshed SYAC release-type note on !%s", mrID)
+}
+
+// ReportBumpConflictIfNeeded is best-effort and never fails the pipeline.
+// It posts a SYAC warning note while the MR's release-type selection is
+// ambiguous, and marks it resolved once it isn't. Runs after PrintSummary,
+// which resolves the selection.
+func ReportBumpConflictIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) {
+	if client == nil || c == nil || c.BumpSelection == nil || !ShouldUpsertMRComment(c) {
+		return
+	}
+	mrID := strings.TrimSpace(c.MRID)
+	sel := *c.BumpSelection
+	if sel.Ambiguous() {
+		logger("[mr] warn: ambiguous release-type selection on !%s: %s", mrID, strings.Join(sel.Conflicts, "; "))
+	}
+	if c.DryRun {
+		if sel.Ambiguous() {
+			logger("[mr] dry-run: would post release-type conflict note on !%s", mrID)
+		}
+		return
+	}
+	if err := c.mrs(client).UpsertBumpConflictNoteCtx(ctx, mrID, sel); err != nil {
+		logger("[mr] warn: upsert conflict note failed: %v", err) // never fail pipeline
+	}
+}
+
+// SyncBumpLabelIfNeeded is best-effort and never fails the pipeline.
+// With SYAC_SYNC_BUMP_LABEL=true it mirrors the MR's release-type selection
+// to its label (e.g. release::minor) so the choice shows up in MR lists.
+// Runs after PrintSummary, which resolves the selection.
+func SyncBumpLabelIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) {
+	if client == nil || c == nil || c.BumpSelection == nil || !c.BumpLabels.Mirror || !c.IsMergeRequest {
+		return
+	}
+	mrID := strings.TrimSpace(c.MRID)
+	if mrID == "" {
+		return
+	}
+	bump := c.BumpSelection.Bump
+	if c.DryRun {
+		logger("[mr] dry-run: would label !%s as %s", mrID, bump)
+		return
+	}
+	if err := c.mrs(client).SetBumpLabelCtx(ctx, mrID, bump); err != nil {
+		logger("[mr] warn: sync release-type label failed: %v", err) // never fail pipeline
+	}
+}
+
+// releaseForecast returns the latest release and the tag each bump would
+// produce on this context's version stream, from a single tag lookup. Each
+// choice goes through the bump policy first, so a maintenance line forecasts
+// the patch it would actually release for Minor and Major too.
+func (c *Context) releaseForecast(ctx context.Context, client *gitlab.Client) (*gitlab.ReleaseForecast, error) {
+	tags := c.tags(client)
+	f := &gitlab.ReleaseForecast{Component: c.Component}
+	latest, err := tags.GetLatestTagCtx(ctx)
+	switch {
+	case err == nil:
+		f.LatestTag = c.TagFormat.Format(latest)
+	case !errors.Is(err, gitlab.ErrNoTags):
+		return nil, err
+	}
+	f.Patch = c.TagFormat.Format(tags.NextVersion(latest, c.clampBump(version.Patch)))
+	f.Minor = c.TagFormat.Format(tags.NextVersion(latest, c.clampBump(version.Minor)))
+	f.Major = c.TagFormat.Format(tags.NextVersion(latest, c.clampBump(version.Major)))
+	return f, nil
+}
+
+// ShouldCreateRCTag gates tagging the commit with NextRCVersion.
+// Conditions:
+//   - Must be a default- or maintenance-branch build (not an MR or tag pipeline)
+//   - An RC version must have been allocated (see PrintSummary)
+//   - The commit must not say [syac no-release]
+//   - Opt-in via SYAC_CREATE_RC_TAG=true
+func ShouldCreateRCTag(c *Context) bool {
+	if c == nil || !(c.IsDefaultBranch || c.IsMaintenanceBranch) || c.IsMergeRequest || c.IsTag || c.Directives.NoRelease {
+		return false
+	}
+	if strings.TrimSpace(c.NextRCVersion) == "" || strings.TrimSpace(c.SHA) == "" {
+		return false
+	}
+	return os.Getenv("SYAC_CREATE_RC_TAG") == "true"
+}
+
+// CreateRCTagIfNeeded is best-effort and never fails the pipeline.
+// It creates the NextRCVersion git tag on the current commit so the next
+// default-branch build allocates rc.N+1.
+func CreateRCTagIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) {
+	if client == nil || c == nil || !ShouldCreateRCTag(c) {
+		return
+	}
+	if c.DryRun {
+		logger("[tags] dry-run: would create RC tag %s on %s", c.NextRCVersion, c.ShortSHA)
+		return
+	}
+
+	msg := fmt.Sprintf("SYAC release candidate %s", c.NextRCVersion)
+	if err := client.Tags.CreateTagCtx(ctx, c.NextRCVersion, c.SHA, msg); err != nil {
+		logger("[tags] warn: create RC tag failed: %v", err) // never fail pipeline
+		return
+	}
+	logger("[tags] created RC tag %s on %s", c.NextRCVersion, c.ShortSHA)
+}
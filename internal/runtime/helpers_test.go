@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestUpsertMRCommentIfNeededShowsForecast(t *testing.T) {
+	t.Setenv("SYAC_MR_COMMENT", "")
+
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: thing"})
+	srv.AddTag("1.4.2", head.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 5}, "")
+	format, err := newTagFormat("", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Context{IsMergeRequest: true, MRID: "5", SHA: head.ID, BumpType: version.Patch, TagFormat: format}
+	client := srv.Client()
+
+	UpsertMRCommentIfNeeded(context.Background(), client, []BuildResult{{Context: c, Pushed: []string{"registry.example.com/app:1.4.3-mr5"}}}, t.Logf)
+	notes := srv.Notes(5)
+	if len(notes) != 1 {
+		t.Fatalf("notes = %d, want 1", len(notes))
+	}
+	for _, want := range []string{
+		"- [ ] **Patch** → `1.4.3`",
+		"- [ ] **Minor** → `1.5.0`",
+		"- [ ] **Major** → `2.0.0`",
+		"Latest release: `1.4.2`",
+		"- `registry.example.com/app:1.4.3-mr5`",
+	} {
+		if !strings.Contains(notes[0].Body, want) {
+			t.Errorf("note missing %q:\n%s", want, notes[0].Body)
+		}
+	}
+
+	// The author picks Minor; the next pipeline keeps it and refreshes the images.
+	body := strings.Replace(notes[0].Body, "- [ ] **Minor**", "- [x] **Minor**", 1)
+	if err := client.MergeRequests.UpdateNote("5", notes[0].ID, body); err != nil {
+		t.Fatal(err)
+	}
+	UpsertMRCommentIfNeeded(context.Background(), client, []BuildResult{{Context: c, Pushed: []string{"registry.example.com/app:1.5.0-mr5"}}}, t.Logf)
+	got := srv.Notes(5)[0].Body
+	if !strings.Contains(got, "- [x] **Minor** → `1.5.0`") || !strings.Contains(got, "app:1.5.0-mr5") || strings.Contains(got, "app:1.4.3-mr5") {
+		t.Fatalf("refreshed note = %s", got)
+	}
+}
+
+func TestUpsertMRCommentIfNeededSharesOneNoteAcrossComponents(t *testing.T) {
+	t.Setenv("SYAC_MR_COMMENT", "")
+	t.Setenv("SYAC_TAG_FORMAT", "")
+
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: thing"})
+	srv.AddTag("api/1.2.3", head.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 5}, "")
+	base := Context{IsMergeRequest: true, MRID: "5", SHA: head.ID, BumpType: version.Patch}
+
+	var builds []BuildResult
+	for _, name := range []string{"api", "web"} {
+		c, err := base.ForComponent(Component{Name: name, Paths: []string{"services/" + name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		builds = append(builds, BuildResult{Context: c, Pushed: []string{"registry.example.com/" + name + ":mr5"}})
+	}
+	UpsertMRCommentIfNeeded(context.Background(), srv.Client(), builds, t.Logf)
+
+	notes := srv.Notes(5)
+	if len(notes) != 1 {
+		t.Fatalf("notes = %d, want one shared note", len(notes))
+	}
+	for _, want := range []string{
+		"- [ ] **Patch** → `api/1.2.4`, `web/0.0.1`",
+		"- [ ] **Minor** → `api/1.3.0`, `web/0.1.0`",
+		"Latest release: `api/1.2.3`, web: none yet",
+		"- `registry.example.com/api:mr5`",
+		"- `registry.example.com/web:mr5`",
+	} {
+		if !strings.Contains(notes[0].Body, want) {
+			t.Errorf("note missing %q:\n%s", want, notes[0].Body)
+		}
+	}
+
+	// One tag lookup per component: the three choices come from the scheme.
+	var lookups int
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r, "GET /api/v4/projects/1/repository/tags") {
+			lookups++
+		}
+	}
+	if lookups != len(builds) {
+		t.Fatalf("tag lookups = %d, want %d", lookups, len(builds))
+	}
+}
+
+func TestUpsertMRCommentIfNeededForecastsMaintenanceLinePatches(t *testing.T) {
+	t.Setenv("SYAC_MR_COMMENT", "")
+
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "fix: backport"})
+	srv.AddTag("1.4.2", head.ID)
+	srv.AddTag("1.5.0", head.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 6}, "")
+	format, err := newTagFormat("", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Context{
+		IsMergeRequest: true, MRID: "6", SHA: head.ID, BumpType: version.Patch, TagFormat: format,
+		MergeRequestTargetBranch: "release/1.4", MaintenanceLine: &version.Line{Major: 1, Minor: 4},
+	}
+
+	// Minor and Major are clamped to Patch on release/1.4, so they can only
+	// ever release 1.4.3; advertising 1.5.x or 2.0.0 would be a lie.
+	UpsertMRCommentIfNeeded(context.Background(), srv.Client(), []BuildResult{{Context: c}}, t.Logf)
+	notes := srv.Notes(6)
+	if len(notes) != 1 {
+		t.Fatalf("notes = %d, want 1", len(notes))
+	}
+	for _, want := range []string{
+		"**Patch** → `1.4.3`",
+		"**Minor** → `1.4.3`",
+		"**Major** → `1.4.3`",
+		"Latest release: `1.4.2`",
+	} {
+		if !strings.Contains(notes[0].Body, want) {
+			t.Errorf("note missing %q:\n%s", want, notes[0].Body)
+		}
+	}
+}
+
+func TestBumpConflictFailsMRPipelineWhenEnabled(t *testing.T) {
+	t.Setenv("SYAC_BUMP", "")
+	t.Setenv("SYAC_BUMP_SOURCES", "")
+	t.Setenv("SYAC_MR_COMMENT", "")
+
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: thing"})
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 9}, "<!-- syac:release-type -->\n- [x] **Minor**\n- [x] **Major**\n")
+	format, err := newTagFormat("", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client()
+
+	for _, fail := range []string{"", "true"} {
+		t.Setenv("SYAC_FAIL_ON_BUMP_CONFLICT", fail)
+		c := Context{IsMergeRequest: true, MRID: "9", SHA: head.ID, ShortSHA: head.ShortID, BumpType: version.Patch, TagFormat: format}
+		err := c.PrintSummary(context.Background(), client)
+		if c.BumpType != version.Minor || c.BumpSelection == nil || !c.BumpSelection.Ambiguous() {
+			t.Fatalf("bump = %s, selection = %+v; want ambiguous Minor", c.BumpType, c.BumpSelection)
+		}
+		if gotFail := errors.Is(err, ErrGuardrail); gotFail != (fail == "true") {
+			t.Fatalf("SYAC_FAIL_ON_BUMP_CONFLICT=%q: PrintSummary err = %v", fail, err)
+		}
+		ReportBumpConflictIfNeeded(context.Background(), client, &c, t.Logf)
+	}
+	if notes := srv.Notes(9); len(notes) != 1 || !strings.Contains(notes[0].Body, "Minor and Major") {
+		t.Fatalf("notes = %+v, want one conflict warning", notes)
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"syac/internal/version"
+)
+
+// defaultMaintenancePattern matches maintenance branches like "release/1.4".
+// A custom SYAC_MAINTENANCE_BRANCH_PATTERN must capture MAJOR and MINOR as
+// the first two groups.
+const defaultMaintenancePattern = `^release/(\d+)\.(\d+)$`
+
+// maintenanceLine reports the MAJOR.MINOR line a branch maintains, if any.
+func maintenanceLine(branch string) (*version.Line, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return nil, nil
+	}
+	pattern := strings.TrimSpace(os.Getenv("SYAC_MAINTENANCE_BRANCH_PATTERN"))
+	if pattern == "" {
+		pattern = defaultMaintenancePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("SYAC_MAINTENANCE_BRANCH_PATTERN: %w", err)
+	}
+	if re.NumSubexp() < 2 {
+		return nil, fmt.Errorf("SYAC_MAINTENANCE_BRANCH_PATTERN %q must capture MAJOR and MINOR", pattern)
+	}
+
+	m := re.FindStringSubmatch(branch)
+	if m == nil {
+		return nil, nil
+	}
+	major, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil, fmt.Errorf("maintenance branch %q: invalid major %q", branch, m[1])
+	}
+	minor, err := strconv.Atoi(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("maintenance branch %q: invalid minor %q", branch, m[2])
+	}
+	return &version.Line{Major: major, Minor: minor}, nil
+}
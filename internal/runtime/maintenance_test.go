@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"testing"
+
+	"syac/internal/version"
+)
+
+func TestMaintenanceLine(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, branch string
+		want            string // "" for no line
+		wantErr         bool
+	}{
+		{"", "release/1.4", "1.4", false},
+		{"", "release/10.0", "10.0", false},
+		{"", "release/1.4.2", "", false},
+		{"", "main", "", false},
+		{"", "", "", false},
+		{`^hotfix-(\d+)-(\d+)$`, "hotfix-2-7", "2.7", false},
+		{`^hotfix-(\d+)-(\d+)$`, "release/1.4", "", false},
+		{`^release/(\d+$`, "release/1.4", "", true}, // does not compile
+		{`^release/(\d+)$`, "release/1", "", true},  // only one group
+		{`^v(\w+)\.(\w+)$`, "v1.x", "", true},       // non-numeric minor
+	} {
+		t.Setenv("SYAC_MAINTENANCE_BRANCH_PATTERN", tc.pattern)
+		line, err := maintenanceLine(tc.branch)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("maintenanceLine(%q) with %q: err = %v, wantErr %v", tc.branch, tc.pattern, err, tc.wantErr)
+			continue
+		}
+		got := ""
+		if line != nil {
+			got = line.String()
+		}
+		if got != tc.want {
+			t.Errorf("maintenanceLine(%q) with %q = %q, want %q", tc.branch, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestResolveFlowMaintenance(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ctx  Context
+		want Flow
+	}{
+		{"maintenance push", Context{IsMaintenanceBranch: true}, FlowMaintenance},
+		{"default wins", Context{IsDefaultBranch: true, IsMaintenanceBranch: true}, FlowDefault},
+		{"MR into maintenance", Context{IsMergeRequest: true, MaintenanceLine: &version.Line{Major: 1, Minor: 4}}, FlowMR},
+		{"tag", Context{IsTag: true, IsMaintenanceBranch: true}, FlowRelease},
+	} {
+		if got := ResolveFlow(tc.ctx, FlowAuto); got != tc.want {
+			t.Errorf("%s: ResolveFlow = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestApplyBumpPolicy(t *testing.T) {
+	line := &version.Line{Major: 1, Minor: 4}
+	for _, tc := range []struct {
+		line     *version.Line
+		bump     version.VersionType
+		want     version.VersionType
+		wantNote bool
+	}{
+		{line, version.Major, version.Patch, true},
+		{line, version.Minor, version.Patch, true},
+		{line, version.Patch, version.Patch, false},
+		{nil, version.Major, version.Major, false},
+	} {
+		c := Context{MaintenanceLine: tc.line, BumpType: tc.bump}
+		note := c.applyBumpPolicy()
+		if c.BumpType != tc.want || (note != "") != tc.wantNote {
+			t.Errorf("applyBumpPolicy(%s, line %v) = %s, %q; want %s (note %v)", tc.bump, tc.line, c.BumpType, note, tc.want, tc.wantNote)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"syac/pkg/gitlab"
+)
+
+// ShouldRelease gates the release step.
+// Conditions:
+//   - Must be a default-branch build (not an MR or tag pipeline)
+//   - Must know the commit SHA
+//   - The commit must not say [syac no-release]
+//   - Opt-in via SYAC_AUTO_RELEASE=true
+func ShouldRelease(c *Context) bool {
+	if c == nil || !c.IsDefaultBranch || c.IsMergeRequest || c.IsTag || c.Directives.NoRelease {
+		return false
+	}
+	if strings.TrimSpace(c.SHA) == "" {
+		return false
+	}
+	return os.Getenv("SYAC_AUTO_RELEASE") == "true"
+}
+
+// ReleaseIfNeeded turns a default-branch build into a release:
+//  1. tag NextVersion (forecast by PrintSummary from the merged MR's selection)
+//     on the commit, or on the version-files commit
+//  2. create a GitLab Release for the tag
+//
+// It is idempotent: a retried pipeline finds the tag already on the commit
+// (Context.ReleaseTag, see PrintSummary) and only creates what is missing.
+// Unlike the MR annotations, failures here are returned so the job fails.
+func ReleaseIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) error {
+	if !ShouldRelease(c) {
+		return nil
+	}
+	if client == nil {
+		return errors.New("release: no GitLab client")
+	}
+
+	tagName := c.ReleaseTag
+	if tagName == "" {
+		if c.NextVersion == "" {
+			return errors.New("release: no version forecast for this commit")
+		}
+		tagName = c.NextVersion
+		ref := firstNonEmpty(c.VersionFilesCommit, c.SHA)
+
+		if c.DryRun {
+			logger("[release] dry-run: would tag %s on %s and create its release", tagName, ref)
+			return nil
+		}
+		if err := client.Tags.CreateTagCtx(ctx, tagName, ref, "Release "+tagName); err != nil {
+			return fmt.Errorf("release: %w", err)
+		}
+		logger("[release] created tag %s on %s (bump %s)", tagName, ref, c.BumpType)
+		c.ReleaseTag = tagName
+	} else {
+		logger("[release] commit already tagged %s; skipping tag creation", tagName)
+	}
+
+	if _, err := client.Releases.GetReleaseCtx(ctx, tagName); err == nil {
+		logger("[release] release %s already exists; nothing to do", tagName)
+		return nil
+	} else if !errors.Is(err, gitlab.ErrReleaseNotFound) {
+		return fmt.Errorf("release: %w", err)
+	}
+	if c.DryRun {
+		logger("[release] dry-run: would create release %s", tagName)
+		return nil
+	}
+
+	payload := gitlab.ReleasePayload{
+		TagName:     tagName,
+		Ref:         c.SHA,
+		Name:        "Release " + tagName,
+		Description: releaseDescription(c.mergedMR(ctx, client)),
+	}
+	if err := client.Releases.CreateReleaseCtx(ctx, payload); err != nil {
+		return fmt.Errorf("release: %w", err)
+	}
+	logger("[release] created release %s", tagName)
+	return nil
+}
+
+// releaseDescription links the release back to the MR that produced it.
+func releaseDescription(mr *gitlab.MergeRequest) string {
+	if mr == nil {
+		return "Released by SYAC."
+	}
+	desc := fmt.Sprintf("Merged via !%d: %s", mr.IID, mr.Title)
+	if mr.WebURL != "" {
+		desc += "\n\n" + mr.WebURL
+	}
+	return desc
+}
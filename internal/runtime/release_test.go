@@ -0,0 +1,123 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+// defaultBranchContext is a default-branch push build of sha.
+func defaultBranchContext(t *testing.T, sha string) Context {
+	t.Helper()
+	format, err := newTagFormat("", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Context{
+		Source:          "push",
+		RefName:         "main",
+		DefaultBranch:   "main",
+		SHA:             sha,
+		ShortSHA:        sha[:8],
+		IsDefaultBranch: true,
+		BumpType:        version.Patch,
+		TagFormat:       format,
+	}
+}
+
+func TestReleaseIfNeededUsesMergedMRAndIsIdempotent(t *testing.T) {
+	t.Setenv("SYAC_AUTO_RELEASE", "true")
+	t.Setenv("SYAC_BUMP", "")
+	t.Setenv("SYAC_BUMP_SOURCES", "")
+
+	srv := gitlabtest.NewServer(t)
+	base := srv.AddCommit(gitlab.Commit{Message: "init"})
+	srv.AddTag("1.2.3", base.ID)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: widgets"})
+	merge := srv.AddCommit(gitlab.Commit{Message: "Merge branch 'widgets'", ParentIDs: []string{base.ID, head.ID}})
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 5, Title: "feat: widgets", State: "merged"},
+		"<!-- syac:release-type -->\n- [ ] **Patch**\n- [x] **Minor**\n- [ ] **Major**", head.ID)
+	client := srv.Client()
+
+	// First run and a retry of the same pipeline.
+	for run := 1; run <= 2; run++ {
+		ctx := defaultBranchContext(t, merge.ID)
+		if err := ctx.PrintSummary(context.Background(), client); err != nil {
+			t.Fatalf("run %d: PrintSummary: %v", run, err)
+		}
+		if ctx.BumpType != version.Minor || ctx.NextVersion != "1.3.0" {
+			t.Fatalf("run %d: bump=%s next=%s, want Minor -> 1.3.0", run, ctx.BumpType, ctx.NextVersion)
+		}
+		if err := ReleaseIfNeeded(context.Background(), client, &ctx, t.Logf); err != nil {
+			t.Fatalf("run %d: ReleaseIfNeeded: %v", run, err)
+		}
+	}
+
+	tags := srv.Tags()
+	if len(tags) != 2 || tags[1].Name != "1.3.0" || tags[1].CommitID() != merge.ID {
+		t.Fatalf("tags = %+v, want 1.2.3 and 1.3.0 on the merge commit", tags)
+	}
+	releases := srv.Releases()
+	if len(releases) != 1 || releases[0].TagName != "1.3.0" {
+		t.Fatalf("releases = %+v, want one release for 1.3.0", releases)
+	}
+}
+
+func TestReleasedTagFollowsOnlyTheVersionFilesCommit(t *testing.T) {
+	t.Setenv("SYAC_BUMP", "")
+	t.Setenv("SYAC_BUMP_SOURCES", "")
+	t.Setenv("SYAC_VERSION_FILES", "VERSION")
+
+	tests := []struct {
+		name        string
+		child       string
+		wantRelease string
+	}{
+		{"retry after version sync", "chore(release): sync version files to 1.3.0 [skip ci]", "1.3.0"},
+		{"unrelated child commit", "fix: later", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := gitlabtest.NewServer(t)
+			base := srv.AddCommit(gitlab.Commit{Message: "init"})
+			srv.AddTag("1.2.3", base.ID)
+			merge := srv.AddCommit(gitlab.Commit{Message: "Merge branch 'widgets'"})
+			child := srv.AddCommit(gitlab.Commit{Message: tt.child})
+			srv.AddTag("1.3.0", child.ID)
+
+			ctx := defaultBranchContext(t, merge.ID)
+			if err := ctx.PrintSummary(context.Background(), srv.Client()); err != nil {
+				t.Fatalf("PrintSummary: %v", err)
+			}
+			if ctx.ReleaseTag != tt.wantRelease {
+				t.Fatalf("ReleaseTag = %q, want %q", ctx.ReleaseTag, tt.wantRelease)
+			}
+			if wantSync := tt.wantRelease != ""; (ctx.VersionFilesCommit == child.ID) != wantSync {
+				t.Fatalf("VersionFilesCommit = %q, want sync commit recorded: %v", ctx.VersionFilesCommit, wantSync)
+			}
+		})
+	}
+}
+
+func TestReleaseIfNeededStopsWhenContextEnds(t *testing.T) {
+	t.Setenv("SYAC_AUTO_RELEASE", "true")
+
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: widgets"})
+	client := srv.Client(gitlab.WithRetryPolicy(gitlab.RetryPolicy{}))
+
+	ctx := defaultBranchContext(t, head.ID)
+	ctx.NextVersion = "1.3.0"
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel() // the job was cancelled before the release step
+	if err := ReleaseIfNeeded(runCtx, client, &ctx, t.Logf); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if tags, reqs := srv.Tags(), srv.Requests(); len(tags) != 0 || len(reqs) != 0 {
+		t.Fatalf("tags = %v, requests = %v; want nothing sent", tags, reqs)
+	}
+}
@@ -0,0 +1,174 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"syac/internal/versionfiles"
+	"syac/pkg/gitlab"
+)
+
+// ShouldSyncVersionFiles gates rewriting SYAC_VERSION_FILES.
+// Conditions:
+//   - SYAC_VERSION_FILES is configured
+//   - Must be a default- or maintenance-branch build (not an MR or tag pipeline)
+//   - The commit must not say [syac no-release]
+//   - A next version must have been forecast (see PrintSummary)
+func ShouldSyncVersionFiles(c *Context) bool {
+	if c == nil || strings.TrimSpace(os.Getenv("SYAC_VERSION_FILES")) == "" {
+		return false
+	}
+	if !(c.IsDefaultBranch || c.IsMaintenanceBranch) || c.IsMergeRequest || c.IsTag || c.Directives.NoRelease {
+		return false
+	}
+	return strings.TrimSpace(c.NextVersion) != ""
+}
+
+// SyncVersionFilesIfNeeded rewrites the configured version files in the
+// workspace (so the image is built with them) and commits every changed file
+// back to the branch in one commit through RepoFilesService. In dry-run it only
+// prints a diff. Paths are relative to CI_PROJECT_DIR, or to the component's
+// directory in a monorepo.
+//
+// The files are computed from the CI_COMMIT_SHA checkout, so the sync fails
+// when anything but version-sync commits landed on the branch since, instead
+// of reverting newer edits; each update also carries the file's last commit so
+// GitLab rejects it if the file changed in between. The commit message names
+// the monorepo component, so a component builds on the sync commits earlier
+// components made in the same run, and a retried pipeline finds and reuses
+// the component's own. The release tag goes on the sync commit (see
+// ReleaseIfNeeded), so the image, the tag and the branch carry the same files.
+func SyncVersionFilesIfNeeded(ctx context.Context, client *gitlab.Client, c *Context, logger func(string, ...any)) error {
+	if !ShouldSyncVersionFiles(c) {
+		return nil
+	}
+	files, err := versionfiles.ParseConfig(os.Getenv("SYAC_VERSION_FILES"))
+	if err != nil {
+		return err
+	}
+
+	v, err := c.TagFormat.Parse(c.NextVersion)
+	if err != nil {
+		return fmt.Errorf("version files: %w", err)
+	}
+	ver := c.TagFormat.Scheme().Format(v)
+
+	dir := firstNonEmpty(os.Getenv("CI_PROJECT_DIR"), ".")
+	prefix := ""
+	if c.Component != "" && c.BuildContext != "" && c.BuildContext != "." {
+		prefix = c.BuildContext
+		dir = filepath.Join(dir, filepath.FromSlash(prefix))
+	}
+
+	changes, err := versionfiles.Apply(dir, files, ver)
+	if err != nil {
+		return fmt.Errorf("version files: %w", err)
+	}
+
+	var actions []gitlab.FileAction
+	for _, ch := range changes {
+		if !ch.Changed() {
+			continue
+		}
+		repoPath := ch.Path
+		if prefix != "" {
+			repoPath = prefix + "/" + ch.Path
+		}
+		if c.DryRun {
+			fmt.Print(versionfiles.Diff(versionfiles.Change{Path: repoPath, Old: ch.Old, New: ch.New}))
+		}
+		actions = append(actions, gitlab.FileAction{Action: "update", FilePath: repoPath, Content: string(ch.New)})
+	}
+	if len(actions) == 0 {
+		logger("[version-files] already at %s; nothing to sync", ver)
+		return nil
+	}
+	if c.DryRun {
+		logger("[version-files] dry-run: would commit %d file(s) at %s to %s", len(actions), ver, c.RefName)
+		return nil
+	}
+
+	if err := versionfiles.Write(dir, changes); err != nil {
+		return fmt.Errorf("version files: %w", err)
+	}
+	if client == nil {
+		return fmt.Errorf("version files: no GitLab client to commit %d file(s)", len(actions))
+	}
+
+	msg := versionFilesMessage(c.Component, ver)
+	synced, foreign, err := versionFilesCommits(ctx, client, c.RefName, c.SHA)
+	if err != nil {
+		return fmt.Errorf("version files: %w", err)
+	}
+	if foreign.ID != "" {
+		return fmt.Errorf("version files: %s moved to %s since %s; refusing to commit files computed from an older tree",
+			c.RefName, firstNonEmpty(foreign.ShortID, foreign.ID), c.ShortSHA)
+	}
+	for _, s := range synced {
+		if s.Title == msg {
+			c.VersionFilesCommit = s.ID
+			logger("[version-files] %s already synced to %s by %s", c.RefName, ver, s.ShortID)
+			return nil
+		}
+	}
+	for i := range actions {
+		f, err := client.Repositories.GetFileCtx(ctx, actions[i].FilePath, c.RefName)
+		if err != nil {
+			return fmt.Errorf("version files: %w", err)
+		}
+		actions[i].LastCommitID = f.LastCommitID
+	}
+
+	commit, err := client.Repositories.CommitFilesCtx(ctx, c.RefName, msg, actions)
+	if err != nil {
+		return fmt.Errorf("version files: %w", err)
+	}
+	c.VersionFilesCommit = commit.ID
+	logger("[version-files] committed %d file(s) at %s to %s (%s)", len(actions), ver, c.RefName, commit.ShortID)
+	return nil
+}
+
+// versionFilesSubjectRe matches the subject of a version-sync commit; group 1
+// is the monorepo component, empty for a single application.
+var versionFilesSubjectRe = regexp.MustCompile(`^chore\(release\): sync (?:(\S+) )?version files to \S+`)
+
+func versionFilesMessage(component, ver string) string {
+	if component != "" {
+		component += " "
+	}
+	return "chore(release): sync " + component + "version files to " + ver + " [skip ci]"
+}
+
+// isVersionFilesCommit reports whether commit is a version-sync commit and,
+// if so, for which component.
+func isVersionFilesCommit(commit gitlab.Commit) (component string, ok bool) {
+	m := versionFilesSubjectRe.FindStringSubmatch(commit.Title)
+	if m == nil || len(commit.ParentIDs) != 1 {
+		return "", false
+	}
+	return m[1], true
+}
+
+// versionFilesCommits walks ref back to sha and returns the version-sync
+// commits stacked on top of it, newest first. foreign is the first other
+// commit found on the way (zero when there is none), i.e. ref moved.
+func versionFilesCommits(ctx context.Context, client *gitlab.Client, ref, sha string) (synced []gitlab.Commit, foreign gitlab.Commit, err error) {
+	for id := ref; ; {
+		commit, err := client.Commits.GetCommitCtx(ctx, id)
+		if err != nil {
+			return nil, gitlab.Commit{}, err
+		}
+		if commit.ID == sha {
+			return synced, gitlab.Commit{}, nil
+		}
+		if _, ok := isVersionFilesCommit(commit); !ok {
+			return synced, commit, nil
+		}
+		synced = append(synced, commit)
+		id = commit.ParentIDs[0]
+	}
+}
@@ -0,0 +1,148 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+// versionFilesWorkspace checks out VERSION at 1.2.3 in a temp CI_PROJECT_DIR.
+func versionFilesWorkspace(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.2.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CI_PROJECT_DIR", dir)
+	t.Setenv("SYAC_VERSION_FILES", "VERSION")
+	return dir
+}
+
+func TestSyncVersionFilesCommitsOnceAcrossRetries(t *testing.T) {
+	dir := versionFilesWorkspace(t)
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: widgets"}, "VERSION")
+	srv.SetFile("main", "VERSION", "1.2.3\n")
+	client := srv.Client()
+
+	var synced string
+	for run := 1; run <= 2; run++ {
+		if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.2.3\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		ctx := defaultBranchContext(t, head.ID)
+		ctx.NextVersion = "1.3.0"
+		if err := SyncVersionFilesIfNeeded(context.Background(), client, &ctx, t.Logf); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		if ctx.VersionFilesCommit == "" || (synced != "" && ctx.VersionFilesCommit != synced) {
+			t.Fatalf("run %d: VersionFilesCommit = %q, want the first run's %q", run, ctx.VersionFilesCommit, synced)
+		}
+		synced = ctx.VersionFilesCommit
+		if got, _ := os.ReadFile(filepath.Join(dir, "VERSION")); string(got) != "1.3.0\n" {
+			t.Fatalf("run %d: workspace VERSION = %q, want 1.3.0", run, got)
+		}
+	}
+
+	commits := srv.Commits()
+	if len(commits) != 2 || commits[1].ID != synced || commits[1].ParentIDs[0] != head.ID {
+		t.Fatalf("commits = %+v, want one sync commit on top of %s", commits, head.ShortID)
+	}
+	if got, _ := srv.File("main", "VERSION"); got != "1.3.0\n" {
+		t.Fatalf("branch VERSION = %q, want 1.3.0", got)
+	}
+	var sawLastCommit bool
+	for _, r := range srv.Requests() {
+		sawLastCommit = sawLastCommit || strings.HasPrefix(r, "GET /api/v4/projects/1/repository/files/VERSION?ref=main")
+	}
+	if !sawLastCommit {
+		t.Fatalf("requests = %v, want the file looked up for its last commit", srv.Requests())
+	}
+}
+
+func TestSyncVersionFilesRefusesMovedBranch(t *testing.T) {
+	versionFilesWorkspace(t)
+	srv := gitlabtest.NewServer(t)
+	built := srv.AddCommit(gitlab.Commit{Message: "feat: widgets"}, "VERSION")
+	srv.AddCommit(gitlab.Commit{Message: "fix: newer"}, "VERSION")
+	srv.SetFile("main", "VERSION", "1.2.4-dev\n")
+
+	ctx := defaultBranchContext(t, built.ID)
+	ctx.NextVersion = "1.3.0"
+	err := SyncVersionFilesIfNeeded(context.Background(), srv.Client(), &ctx, t.Logf)
+	if err == nil || !strings.Contains(err.Error(), "moved") {
+		t.Fatalf("err = %v, want branch moved", err)
+	}
+	if got, _ := srv.File("main", "VERSION"); got != "1.2.4-dev\n" || len(srv.Commits()) != 2 || ctx.VersionFilesCommit != "" {
+		t.Fatalf("branch VERSION = %q, commits = %d; want the newer edit untouched", got, len(srv.Commits()))
+	}
+}
+
+func TestSyncVersionFilesStacksComponentsInOneRun(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		versions map[string]string
+	}{
+		{"different versions", map[string]string{"api": "1.3.0", "worker": "0.1.0"}},
+		{"same version", map[string]string{"api": "1.0.0", "worker": "1.0.0"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("CI_PROJECT_DIR", dir)
+			t.Setenv("SYAC_VERSION_FILES", "VERSION")
+			t.Setenv("SYAC_TAG_FORMAT", "")
+			srv := gitlabtest.NewServer(t)
+			head := srv.AddCommit(gitlab.Commit{Message: "feat: both"}, "services/api/VERSION", "services/worker/VERSION")
+			components := []Component{{Name: "api", Paths: []string{"services/api"}}, {Name: "worker", Paths: []string{"services/worker"}}}
+			for _, comp := range components {
+				srv.SetFile("main", comp.Paths[0]+"/VERSION", "0.0.0\n")
+			}
+
+			// Two runs: the second is a retry that must reuse both commits.
+			synced := map[string]string{}
+			for run := 1; run <= 2; run++ {
+				for _, comp := range components {
+					if err := os.MkdirAll(filepath.Join(dir, comp.Paths[0]), 0o755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(filepath.Join(dir, comp.Paths[0], "VERSION"), []byte("0.0.0\n"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					c, err := defaultBranchContext(t, head.ID).ForComponent(comp)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if run == 2 && c.versionFilesCommit(context.Background(), srv.Client()) != synced[comp.Name] {
+						t.Fatalf("retry: %s bound to %q, want its own sync commit %q", comp.Name, c.VersionFilesCommit, synced[comp.Name])
+					}
+					c.NextVersion = comp.Name + "/" + tc.versions[comp.Name]
+					if err := SyncVersionFilesIfNeeded(context.Background(), srv.Client(), &c, t.Logf); err != nil {
+						t.Fatalf("run %d, %s: %v", run, comp.Name, err)
+					}
+					if run == 2 && c.VersionFilesCommit != synced[comp.Name] {
+						t.Fatalf("retry: %s VersionFilesCommit = %q, want %q", comp.Name, c.VersionFilesCommit, synced[comp.Name])
+					}
+					synced[comp.Name] = c.VersionFilesCommit
+				}
+			}
+
+			commits := srv.Commits()
+			if len(commits) != 3 || commits[2].ParentIDs[0] != commits[1].ID || commits[1].ParentIDs[0] != head.ID {
+				t.Fatalf("commits = %+v, want one sync commit per component stacked on %s", commits, head.ShortID)
+			}
+			if synced["api"] != commits[1].ID || synced["worker"] != commits[2].ID {
+				t.Fatalf("synced = %v, want api then worker", synced)
+			}
+			for _, comp := range components {
+				if got, _ := srv.File("main", comp.Paths[0]+"/VERSION"); got != tc.versions[comp.Name]+"\n" {
+					t.Errorf("%s VERSION = %q, want %s", comp.Name, got, tc.versions[comp.Name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package transport builds the HTTP transport syac uses for outbound calls
+// (GitLab API, and any registry HTTP calls): extra CA bundles, mTLS client
+// certificates, explicit proxy / no-proxy and an opt-in insecure mode.
+//
+// docker login/push go through the Docker daemon, which keeps its own trust
+// store (/etc/docker/certs.d) and proxy settings; this package does not
+// change them.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config describes TLS and proxy settings for outbound HTTP.
+type Config struct {
+	CAFile string // extra CA bundle (PEM file), added to the system pool
+	CAPEM  string // extra CA bundle (PEM contents), added to the system pool
+
+	CertFile string // client certificate for mTLS (PEM)
+	KeyFile  string // client key for mTLS (PEM)
+
+	ProxyURL string // explicit proxy; empty uses HTTP(S)_PROXY / NO_PROXY
+	NoProxy  string // comma-separated hosts, domains (.corp), IPs or CIDRs bypassing ProxyURL
+
+	Insecure bool // skip TLS verification (lab instances only)
+}
+
+// FromEnv reads the SYAC_* transport variables:
+//   - SYAC_CA_FILE (falls back to CI_SERVER_TLS_CA_FILE from the runner) / SYAC_CA_PEM
+//   - SYAC_CLIENT_CERT_FILE + SYAC_CLIENT_KEY_FILE
+//   - SYAC_PROXY_URL + SYAC_NO_PROXY
+//   - SYAC_TLS_INSECURE=true
+func FromEnv() Config {
+	return Config{
+		CAFile:   firstEnv("SYAC_CA_FILE", "CI_SERVER_TLS_CA_FILE"),
+		CAPEM:    os.Getenv("SYAC_CA_PEM"),
+		CertFile: firstEnv("SYAC_CLIENT_CERT_FILE"),
+		KeyFile:  firstEnv("SYAC_CLIENT_KEY_FILE"),
+		ProxyURL: firstEnv("SYAC_PROXY_URL"),
+		NoProxy:  firstEnv("SYAC_NO_PROXY"),
+		Insecure: os.Getenv("SYAC_TLS_INSECURE") == "true",
+	}
+}
+
+// IsZero reports whether c changes nothing from Go's default transport.
+func (c Config) IsZero() bool {
+	return c == Config{}
+}
+
+// HTTPClient returns an http.Client using c's transport and timeout.
+func (c Config) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	t, err := c.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: t, Timeout: timeout}, nil
+}
+
+// Transport returns a clone of http.DefaultTransport configured by c.
+func (c Config) Transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" || c.CAPEM != "" {
+		pool, err := c.certPool()
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("transport: mTLS needs both SYAC_CLIENT_CERT_FILE and SYAC_CLIENT_KEY_FILE")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("transport: load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	if c.Insecure {
+		fmt.Fprintln(os.Stderr, "warning: TLS verification disabled (SYAC_TLS_INSECURE=true)")
+		tlsCfg.InsecureSkipVerify = true
+	}
+	t.TLSClientConfig = tlsCfg
+
+	if c.ProxyURL != "" {
+		proxy, err := url.Parse(c.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("transport: invalid proxy URL %q", c.ProxyURL)
+		}
+		noProxy := parseNoProxy(c.NoProxy)
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			if noProxy.matches(req.URL.Hostname()) {
+				return nil, nil
+			}
+			return proxy, nil
+		}
+	}
+	return t, nil
+}
+
+// certPool is the system pool plus the configured CA bundle(s).
+func (c Config) certPool() (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("transport: read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("transport: no certificates in %s", c.CAFile)
+		}
+	}
+	if c.CAPEM != "" && !pool.AppendCertsFromPEM([]byte(c.CAPEM)) {
+		return nil, errors.New("transport: no certificates in SYAC_CA_PEM")
+	}
+	return pool, nil
+}
+
+// noProxyList is a parsed NO_PROXY-style list.
+type noProxyList struct {
+	all     bool
+	domains []string // lower-case, without leading "."
+	nets    []*net.IPNet
+}
+
+func parseNoProxy(s string) noProxyList {
+	var l noProxyList
+	for _, e := range strings.Split(s, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+		case e == "*":
+			l.all = true
+		default:
+			if _, n, err := net.ParseCIDR(e); err == nil {
+				l.nets = append(l.nets, n)
+				continue
+			}
+			if h, _, err := net.SplitHostPort(e); err == nil {
+				e = h // ports are ignored
+			}
+			l.domains = append(l.domains, strings.TrimPrefix(e, "."))
+		}
+	}
+	return l
+}
+
+// matches reports whether host (no port) bypasses the proxy: an exact match,
+// a subdomain of a listed domain, or an IP inside a listed CIDR.
+func (l noProxyList) matches(host string) bool {
+	if l.all {
+		return true
+	}
+	host = strings.ToLower(host)
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range l.nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	for _, d := range l.domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
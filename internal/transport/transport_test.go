@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExtraCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cfg := range map[string]Config{
+		"file":     {CAFile: caFile},
+		"pem":      {CAPEM: string(caPEM)},
+		"insecure": {Insecure: true},
+	} {
+		hc, err := cfg.HTTPClient(5 * time.Second)
+		if err != nil {
+			t.Fatalf("%s: HTTPClient: %v", name, err)
+		}
+		resp, err := hc.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("%s: GET: %v", name, err)
+		}
+		resp.Body.Close()
+	}
+
+	hc, _ := Config{}.HTTPClient(5 * time.Second)
+	if _, err := hc.Get(srv.URL); err == nil {
+		t.Fatal("default trust store should reject the test CA")
+	}
+}
+
+func TestProxyAndNoProxy(t *testing.T) {
+	tr, err := Config{ProxyURL: "http://proxy.corp:3128", NoProxy: ".internal.corp, gitlab.corp:443, 10.0.0.0/8"}.Transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for target, wantProxy := range map[string]bool{
+		"https://registry.example.com/v2/": true,
+		"https://gitlab.corp/api/v4":       false,
+		"https://api.internal.corp/":       false,
+		"https://notinternal.corp/":        true,
+		"http://10.1.2.3:8080/":            false,
+		"http://192.168.1.1/":              true,
+	} {
+		req, _ := http.NewRequest("GET", target, nil)
+		proxy, err := tr.Proxy(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := proxy != nil; got != wantProxy {
+			t.Errorf("%s: proxied=%v, want %v", target, got, wantProxy)
+		}
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"missing CA":  {CAFile: filepath.Join(t.TempDir(), "nope.pem")},
+		"garbage PEM": {CAPEM: "not a cert"},
+		"cert only":   {CertFile: "client.pem"},
+		"bad proxy":   {ProxyURL: "::"},
+	} {
+		if _, err := cfg.Transport(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCalVerLayout is used by ParseScheme("calver").
+const DefaultCalVerLayout = "YYYY.MM.MICRO"
+
+// CalVer is a calendar versioning scheme (https://calver.org). The layout has
+// exactly three dot-separated tokens: a year (YYYY, YY, 0Y), a period (MM, 0M,
+// WW, 0W) and a counter (MICRO or N). They are stored as Major, Minor and Patch;
+// pre-release and build metadata work as in SemVer (2024.5.0-rc.1).
+type CalVer struct {
+	Layout string
+
+	// Now returns the release date; defaults to time.Now in UTC.
+	Now func() time.Time
+
+	tokens [3]string
+}
+
+// NewCalVer validates layout (DefaultCalVerLayout if empty).
+func NewCalVer(layout string) (*CalVer, error) {
+	if strings.TrimSpace(layout) == "" {
+		layout = DefaultCalVerLayout
+	}
+	parts := strings.Split(layout, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid calver layout %q: expected <year>.<period>.<counter>", layout)
+	}
+	c := &CalVer{Layout: layout}
+	valid := [3][]string{{"YYYY", "YY", "0Y"}, {"MM", "0M", "WW", "0W"}, {"MICRO", "N"}}
+	for i, p := range parts {
+		if !contains(valid[i], p) {
+			return nil, fmt.Errorf("invalid calver layout %q: token %q must be one of %s", layout, p, strings.Join(valid[i], ", "))
+		}
+		c.tokens[i] = p
+	}
+	return c, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CalVer) Name() string { return "calver:" + c.Layout }
+
+// Parse reads e.g. "2024.05.3" or "24.07.1-rc.2" (zero padding is optional).
+func (c *CalVer) Parse(s string) (Version, error) {
+	var build, pre string
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s, build = s[:i], s[i+1:]
+		if err := validateIdentifiers(build, false); err != nil {
+			return Version{}, fmt.Errorf("invalid build metadata: %w", err)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, pre = s[:i], s[i+1:]
+		if err := validateIdentifiers(pre, true); err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release: %w", err)
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid calver %q: expected %s", s, c.Layout)
+	}
+	var nums [3]int
+	for i, p := range parts {
+		if !isNumeric(p) {
+			return Version{}, fmt.Errorf("invalid calver %q: %q is not a number", s, p)
+		}
+		nums[i], _ = strconv.Atoi(p)
+	}
+
+	if c.tokens[0] == "YYYY" && nums[0] < 1000 {
+		return Version{}, fmt.Errorf("invalid calver %q: year must have 4 digits", s)
+	}
+	switch c.tokens[1] {
+	case "MM", "0M":
+		if nums[1] < 1 || nums[1] > 12 {
+			return Version{}, fmt.Errorf("invalid calver %q: month out of range", s)
+		}
+	case "WW", "0W":
+		if nums[1] < 1 || nums[1] > 53 {
+			return Version{}, fmt.Errorf("invalid calver %q: week out of range", s)
+		}
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], PreRelease: pre, Build: build}, nil
+}
+
+// Format writes v with the layout's padding, e.g. YY.0W.N → "24.07.1".
+func (c *CalVer) Format(v Version) string {
+	s := fmt.Sprintf("%s.%s.%d", c.formatToken(c.tokens[0], v.Major), c.formatToken(c.tokens[1], v.Minor), v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+func (c *CalVer) formatToken(tok string, n int) string {
+	if strings.HasPrefix(tok, "0") {
+		return fmt.Sprintf("%02d", n)
+	}
+	return strconv.Itoa(n)
+}
+
+// Compare orders numerically, then by pre-release like SemVer.
+func (c *CalVer) Compare(a, b Version) int { return a.Compare(b) }
+
+// Next ignores the bump level: the release date decides. Releasing again in the
+// same period increments the counter; a new period restarts it at 0. A pending
+// pre-release of the current period is promoted to its release.
+func (c *CalVer) Next(current Version, _ VersionType) Version {
+	year, period := c.period(c.now())
+	if current.Major == year && current.Minor == period {
+		if current.IsPreRelease() {
+			return Version{Major: year, Minor: period, Patch: current.Patch}
+		}
+		return Version{Major: year, Minor: period, Patch: current.Patch + 1}
+	}
+	return Version{Major: year, Minor: period, Patch: 0}
+}
+
+func (c *CalVer) NextPreRelease(base Version, id string, existing []Version) Version {
+	return NextPreRelease(base, id, existing)
+}
+
+func (c *CalVer) now() time.Time {
+	if c.Now != nil {
+		return c.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// period returns the (year, month|week) numbers for t as stored in Major/Minor.
+func (c *CalVer) period(t time.Time) (int, int) {
+	year := t.Year()
+	second := int(t.Month())
+	if c.tokens[1] == "WW" || c.tokens[1] == "0W" {
+		year, second = t.ISOWeek()
+	}
+	if c.tokens[0] != "YYYY" {
+		year -= 2000
+	}
+	return year, second
+}
@@ -0,0 +1,113 @@
+package version
+
+import (
+	"testing"
+	"time"
+)
+
+func fixedNow(y int, m time.Month, d int) func() time.Time {
+	return func() time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) }
+}
+
+func TestParseScheme(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		name string
+		err  bool
+	}{
+		{"", "semver", false},
+		{"semver", "semver", false},
+		{"calver", "calver:YYYY.MM.MICRO", false},
+		{"calver:YY.0W.N", "calver:YY.0W.N", false},
+		{"calver:YYYY.DD.MICRO", "", true},
+		{"calver:YYYY.MM", "", true},
+		{"semver:X.Y.Z", "", true},
+		{"romver", "", true},
+	} {
+		s, err := ParseScheme(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("ParseScheme(%q) expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil || s.Name() != tc.name {
+			t.Errorf("ParseScheme(%q) = %v, %v; want %s", tc.in, s, err, tc.name)
+		}
+	}
+}
+
+func TestCalVerParseFormat(t *testing.T) {
+	monthly, _ := NewCalVer("YYYY.MM.MICRO")
+	weekly, _ := NewCalVer("YY.0W.N")
+
+	tests := []struct {
+		c    *CalVer
+		in   string
+		want Version
+		out  string
+	}{
+		{monthly, "2024.5.3", Version{Major: 2024, Minor: 5, Patch: 3}, "2024.5.3"},
+		{monthly, "2024.05.0-rc.1", Version{Major: 2024, Minor: 5, PreRelease: "rc.1"}, "2024.5.0-rc.1"},
+		{weekly, "24.07.1", Version{Major: 24, Minor: 7, Patch: 1}, "24.07.1"},
+		{weekly, "24.7.1", Version{Major: 24, Minor: 7, Patch: 1}, "24.07.1"},
+	}
+	for _, tt := range tests {
+		got, err := tt.c.Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("%s Parse(%q) = %+v, %v; want %+v", tt.c.Name(), tt.in, got, err, tt.want)
+			continue
+		}
+		if out := tt.c.Format(got); out != tt.out {
+			t.Errorf("%s Format = %q; want %q", tt.c.Name(), out, tt.out)
+		}
+	}
+
+	for _, bad := range []string{"24.5.1", "2024.13.0", "2024.5", "2024.x.1"} {
+		if _, err := monthly.Parse(bad); err == nil {
+			t.Errorf("monthly Parse(%q) expected error", bad)
+		}
+	}
+	if _, err := weekly.Parse("24.54.0"); err == nil {
+		t.Errorf("weekly Parse(24.54.0) expected error")
+	}
+}
+
+func TestCalVerNext(t *testing.T) {
+	monthly, _ := NewCalVer("YYYY.MM.MICRO")
+	monthly.Now = fixedNow(2024, time.May, 20)
+	weekly, _ := NewCalVer("YY.0W.N")
+	weekly.Now = fixedNow(2024, time.February, 14) // ISO week 7
+
+	tests := []struct {
+		c       *CalVer
+		current Version
+		want    string
+	}{
+		{monthly, Version{}, "2024.5.0"},
+		{monthly, Version{Major: 2024, Minor: 4, Patch: 9}, "2024.5.0"},
+		{monthly, Version{Major: 2024, Minor: 5, Patch: 2}, "2024.5.3"},
+		{monthly, Version{Major: 2024, Minor: 5, Patch: 3, PreRelease: "rc.2"}, "2024.5.3"},
+		{weekly, Version{Major: 24, Minor: 7, Patch: 0}, "24.07.1"},
+		{weekly, Version{Major: 24, Minor: 6, Patch: 4}, "24.07.0"},
+	}
+	for _, tt := range tests {
+		for _, bump := range []VersionType{Patch, Major} {
+			if got := tt.c.Format(tt.c.Next(tt.current, bump)); got != tt.want {
+				t.Errorf("%s Next(%+v, %s) = %s; want %s", tt.c.Name(), tt.current, bump, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestTagFormatWithCalVer(t *testing.T) {
+	cv, _ := NewCalVer("YY.0W.N")
+	cv.Now = fixedNow(2024, time.February, 14)
+	f, _ := NewTagFormat("v{{.Version}}", "")
+	f = f.WithScheme(cv)
+
+	got, err := f.ForecastNext("v24.07.0", Minor)
+	if err != nil || got != "v24.07.1" {
+		t.Errorf("ForecastNext = %q, %v; want v24.07.1", got, err)
+	}
+}
@@ -0,0 +1,63 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConventionalCommit is the parsed header (and breaking-change footer) of a
+// Conventional Commits 1.0 message, e.g. "feat(api)!: drop v1 endpoints".
+type ConventionalCommit struct {
+	Type     string // feat, fix, chore, ...
+	Scope    string // optional, without parentheses
+	Breaking bool   // "!" in header or a BREAKING CHANGE footer
+	Subject  string
+}
+
+var (
+	conventionalHeaderRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+	breakingFooterRe     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: \S`)
+)
+
+// ParseConventionalCommit parses a full commit message. It returns false when the
+// first line is not a Conventional Commit header.
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	m := conventionalHeaderRe.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:     strings.ToLower(m[1]),
+		Scope:    m[2],
+		Breaking: m[3] == "!" || breakingFooterRe.MatchString(body),
+		Subject:  m[4],
+	}, true
+}
+
+// Bump maps the commit to a version bump: breaking → Major, feat → Minor,
+// fix/perf → Patch. Other types (chore, docs, ci, ...) do not release anything.
+func (cc ConventionalCommit) Bump() (VersionType, bool) {
+	switch {
+	case cc.Breaking:
+		return Major, true
+	case cc.Type == "feat":
+		return Minor, true
+	case cc.Type == "fix" || cc.Type == "perf":
+		return Patch, true
+	}
+	return "", false
+}
+
+// Rank orders bump levels (Patch < Minor < Major). Unknown types rank 0.
+func (vt VersionType) Rank() int {
+	switch vt {
+	case Patch:
+		return 1
+	case Minor:
+		return 2
+	case Major:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package version
+
+import "testing"
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		ok       bool
+		want     ConventionalCommit
+		wantBump VersionType
+	}{
+		{
+			name:     "feat",
+			message:  "feat: add login",
+			ok:       true,
+			want:     ConventionalCommit{Type: "feat", Subject: "add login"},
+			wantBump: Minor,
+		},
+		{
+			name:     "fix with scope",
+			message:  "fix(api): handle nil body",
+			ok:       true,
+			want:     ConventionalCommit{Type: "fix", Scope: "api", Subject: "handle nil body"},
+			wantBump: Patch,
+		},
+		{
+			name:     "bang marks breaking",
+			message:  "refactor(core)!: drop legacy config",
+			ok:       true,
+			want:     ConventionalCommit{Type: "refactor", Scope: "core", Breaking: true, Subject: "drop legacy config"},
+			wantBump: Major,
+		},
+		{
+			name:     "breaking footer",
+			message:  "feat: new auth\n\nBREAKING CHANGE: tokens must be rotated",
+			ok:       true,
+			want:     ConventionalCommit{Type: "feat", Breaking: true, Subject: "new auth"},
+			wantBump: Major,
+		},
+		{
+			name:    "chore does not bump",
+			message: "chore: bump deps",
+			ok:      true,
+			want:    ConventionalCommit{Type: "chore", Subject: "bump deps"},
+		},
+		{
+			name:    "merge commit",
+			message: "Merge branch 'gmarm-12' into 'dev'",
+		},
+		{
+			name:    "missing space after colon",
+			message: "feat:add login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseConventionalCommit(tt.message)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v; want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v; want %+v", got, tt.want)
+			}
+			bump, _ := got.Bump()
+			if bump != tt.wantBump {
+				t.Errorf("Bump() = %q; want %q", bump, tt.wantBump)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	if !(Patch.Rank() < Minor.Rank() && Minor.Rank() < Major.Rank()) {
+		t.Errorf("expected Patch < Minor < Major")
+	}
+	if VersionType("unknown").Rank() != 0 {
+		t.Errorf("unknown bump should rank 0")
+	}
+}
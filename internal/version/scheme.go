@@ -0,0 +1,86 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scheme is a versioning scheme: how versions are written, ordered and bumped.
+// Versions are always stored in the Major/Minor/Patch fields of Version; a scheme
+// decides what those numbers mean (e.g. CalVer uses year/month/micro).
+type Scheme interface {
+	// Name identifies the scheme, e.g. "semver" or "calver:YYYY.MM.MICRO".
+	Name() string
+	// Parse reads a version written in this scheme (no tag prefix/suffix).
+	Parse(s string) (Version, error)
+	// Format writes v in this scheme.
+	Format(v Version) string
+	// Compare returns -1, 0 or +1 by precedence.
+	Compare(a, b Version) int
+	// Next returns the release that follows current for the given bump.
+	Next(current Version, bump VersionType) Version
+	// NextPreRelease allocates the next "<id>.N" pre-release of base.
+	NextPreRelease(base Version, id string, existing []Version) Version
+}
+
+// SemVerScheme is Semantic Versioning 2.0, the default scheme.
+//
+// With InitialDevelopment set, 0.y.z versions shift bumps down one level
+// (Major → minor, Minor → patch), since a breaking change during initial
+// development doesn't mean 1.0.0. Versions >= 1.0.0 bump normally.
+type SemVerScheme struct {
+	InitialDevelopment bool
+}
+
+func (s SemVerScheme) Name() string {
+	if s.InitialDevelopment {
+		return "semver (0.x mode)"
+	}
+	return "semver"
+}
+
+func (SemVerScheme) Parse(s string) (Version, error) { return Parse(s) }
+func (SemVerScheme) Format(v Version) string         { return v.String() }
+func (SemVerScheme) Compare(a, b Version) int        { return a.Compare(b) }
+
+func (s SemVerScheme) Next(v Version, b VersionType) Version {
+	return v.Increment(s.EffectiveBump(v, b))
+}
+
+// EffectiveBump returns the bump Next applies to v for b: one level lower on
+// 0.y.z in InitialDevelopment mode, b otherwise.
+func (s SemVerScheme) EffectiveBump(v Version, b VersionType) VersionType {
+	if s.InitialDevelopment && v.Major == 0 {
+		switch b {
+		case Major:
+			return Minor
+		case Minor:
+			return Patch
+		}
+	}
+	return b
+}
+
+func (SemVerScheme) NextPreRelease(base Version, id string, existing []Version) Version {
+	return NextPreRelease(base, id, existing)
+}
+
+// ParseScheme selects a scheme by name (SYAC_VERSION_SCHEME):
+//   - "" or "semver"        → SemVerScheme
+//   - "calver"              → CalVer with DefaultCalVerLayout
+//   - "calver:<layout>"     → CalVer with the given layout, e.g. "calver:YY.0W.N"
+func ParseScheme(name string) (Scheme, error) {
+	name = strings.TrimSpace(name)
+	kind, layout, _ := strings.Cut(name, ":")
+	switch strings.ToLower(kind) {
+	case "", "semver":
+		if layout != "" {
+			return nil, fmt.Errorf("invalid version scheme %q: semver takes no layout", name)
+		}
+		return SemVerScheme{}, nil
+	case "calver":
+		return NewCalVer(layout)
+	default:
+		return nil, fmt.Errorf("invalid version scheme %q. Must be one of: semver, calver[:<layout>]", name)
+	}
+}
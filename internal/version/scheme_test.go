@@ -0,0 +1,28 @@
+package version
+
+import "testing"
+
+func TestSemVerInitialDevelopment(t *testing.T) {
+	s := SemVerScheme{InitialDevelopment: true}
+	tests := []struct {
+		from string
+		bump VersionType
+		want string
+	}{
+		{"0.3.2", Major, "0.4.0"},
+		{"0.3.2", Minor, "0.3.3"},
+		{"0.3.2", Patch, "0.3.3"},
+		{"0.4.0-rc.1", Major, "0.4.0"},
+		{"1.2.3", Major, "2.0.0"},
+		{"1.2.3", Minor, "1.3.0"},
+	}
+	for _, tt := range tests {
+		v, _ := Parse(tt.from)
+		if got := s.Next(v, tt.bump).String(); got != tt.want {
+			t.Errorf("0.x Next(%s, %s) = %s; want %s", tt.from, tt.bump, got, tt.want)
+		}
+	}
+	if got := (SemVerScheme{}).Next(Version{Minor: 3}, Major).String(); got != "1.0.0" {
+		t.Errorf("default Next(0.3.0, Major) = %s; want 1.0.0", got)
+	}
+}
@@ -0,0 +1,114 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultTagFormat is the bare SemVer tag style ("1.2.3") syac has always used.
+const DefaultTagFormat = "{{.Version}}"
+
+// versionPlaceholder is rendered in place of {{.Version}} to locate the
+// literal text around it; it can never appear in a real tag.
+const versionPlaceholder = "\x00version\x00"
+
+// TagFormat maps versions to git tag names and back, driven by a text/template
+// such as "v{{.Version}}" or "{{.App}}-{{.Version}}". The template must render
+// {{.Version}} exactly once; everything else must be static for a given App.
+// The version itself is written by the format's Scheme (SemVer by default).
+type TagFormat struct {
+	Template string
+	App      string
+
+	prefix string
+	suffix string
+	scheme Scheme
+}
+
+// NewTagFormat validates tmpl (DefaultTagFormat if empty) and binds it to app.
+func NewTagFormat(tmpl, app string) (TagFormat, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultTagFormat
+	}
+	t, err := template.New("tag").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return TagFormat{}, fmt.Errorf("invalid tag format %q: %w", tmpl, err)
+	}
+
+	var b strings.Builder
+	data := struct{ Version, App string }{Version: versionPlaceholder, App: app}
+	if err := t.Execute(&b, data); err != nil {
+		return TagFormat{}, fmt.Errorf("invalid tag format %q: %w", tmpl, err)
+	}
+	out := b.String()
+	if strings.Count(out, versionPlaceholder) != 1 {
+		return TagFormat{}, fmt.Errorf("invalid tag format %q: must contain {{.Version}} exactly once", tmpl)
+	}
+	prefix, suffix, _ := strings.Cut(out, versionPlaceholder)
+
+	return TagFormat{Template: tmpl, App: app, prefix: prefix, suffix: suffix}, nil
+}
+
+// WithScheme returns a copy of f that reads and writes versions with scheme.
+func (f TagFormat) WithScheme(scheme Scheme) TagFormat {
+	f.scheme = scheme
+	return f
+}
+
+// Scheme returns the versioning scheme, SemVerScheme if none was set.
+func (f TagFormat) Scheme() Scheme {
+	if f.scheme == nil {
+		return SemVerScheme{}
+	}
+	return f.scheme
+}
+
+// String returns the template text.
+func (f TagFormat) String() string {
+	if f.Template == "" {
+		return DefaultTagFormat
+	}
+	return f.Template
+}
+
+// Prefix returns the literal text before the version, e.g. "v" or "api/".
+// Useful for narrowing tag searches server-side.
+func (f TagFormat) Prefix() string {
+	return f.prefix
+}
+
+// Format renders the tag name for v, e.g. v1.2.3 or api-1.2.3.
+func (f TagFormat) Format(v Version) string {
+	return f.prefix + f.Scheme().Format(v) + f.suffix
+}
+
+// Parse extracts the version from a tag name rendered by this format.
+// Tags that don't match the format's literal text are rejected, so
+// "v1.2.3" is not a match for the default "{{.Version}}" format.
+func (f TagFormat) Parse(tag string) (Version, error) {
+	core, ok := strings.CutPrefix(tag, f.prefix)
+	if ok {
+		core, ok = strings.CutSuffix(core, f.suffix)
+	}
+	if !ok || strings.HasPrefix(core, "v") {
+		return Version{}, fmt.Errorf("tag %q does not match format %q", tag, f.String())
+	}
+	return f.Scheme().Parse(core)
+}
+
+// ForecastNext parses latestTag with this format, bumps it through the scheme,
+// and renders the next tag name. An empty latestTag is treated as 0.0.0.
+func (f TagFormat) ForecastNext(latestTag string, bump VersionType) (string, error) {
+	latestTag = strings.TrimSpace(latestTag)
+
+	v := Version{}
+	if latestTag != "" {
+		parsed, err := f.Parse(latestTag)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse latest tag %q: %w", latestTag, err)
+		}
+		v = parsed
+	}
+	return f.Format(f.Scheme().Next(v, bump)), nil
+}
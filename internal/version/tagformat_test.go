@@ -0,0 +1,81 @@
+package version
+
+import "testing"
+
+func TestTagFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		app     string
+		version Version
+		tag     string
+	}{
+		{"default", "", "", Version{Major: 1, Minor: 2, Patch: 3}, "1.2.3"},
+		{"v prefix", "v{{.Version}}", "", Version{Major: 1, Minor: 2, Patch: 3}, "v1.2.3"},
+		{"app prefix", "{{.App}}-{{.Version}}", "api", Version{Major: 0, Minor: 9, Patch: 0}, "api-0.9.0"},
+		{"pre-release", "v{{.Version}}", "", Version{Major: 1, PreRelease: "rc.2"}, "v1.0.0-rc.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTagFormat(tt.tmpl, tt.app)
+			if err != nil {
+				t.Fatalf("NewTagFormat(%q) unexpected error: %v", tt.tmpl, err)
+			}
+			if got := f.Format(tt.version); got != tt.tag {
+				t.Errorf("Format = %q; want %q", got, tt.tag)
+			}
+			got, err := f.Parse(tt.tag)
+			if err != nil || got != tt.version {
+				t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.tag, got, err, tt.version)
+			}
+		})
+	}
+}
+
+func TestTagFormatRejects(t *testing.T) {
+	def, _ := NewTagFormat("", "")
+	vf, _ := NewTagFormat("v{{.Version}}", "")
+	app, _ := NewTagFormat("{{.App}}-{{.Version}}", "api")
+
+	for _, tc := range []struct {
+		f   TagFormat
+		tag string
+	}{
+		{def, "v1.2.3"},
+		{vf, "1.2.3"},
+		{app, "worker-1.2.3"},
+		{app, "api-latest"},
+	} {
+		if _, err := tc.f.Parse(tc.tag); err == nil {
+			t.Errorf("format %q: Parse(%q) expected error", tc.f, tc.tag)
+		}
+	}
+}
+
+func TestNewTagFormatInvalid(t *testing.T) {
+	for _, tmpl := range []string{"release", "{{.Version}}-{{.Version}}", "{{.Version", "{{.Nope}}-{{.Version}}"} {
+		if _, err := NewTagFormat(tmpl, "api"); err == nil {
+			t.Errorf("NewTagFormat(%q) expected error", tmpl)
+		}
+	}
+}
+
+func TestTagFormatForecastNext(t *testing.T) {
+	f, _ := NewTagFormat("{{.App}}-{{.Version}}", "api")
+
+	got, err := f.ForecastNext("api-1.4.2", Minor)
+	if err != nil || got != "api-1.5.0" {
+		t.Errorf("ForecastNext = %q, %v; want api-1.5.0", got, err)
+	}
+	got, err = f.ForecastNext("", Patch)
+	if err != nil || got != "api-0.0.1" {
+		t.Errorf("ForecastNext(empty) = %q, %v; want api-0.0.1", got, err)
+	}
+
+	// Legacy helper keeps the "v" prefix style of the input.
+	got, err = ForecastNext("v1.2.3", Patch)
+	if err != nil || got != "v1.2.4" {
+		t.Errorf("ForecastNext(v1.2.3) = %q, %v; want v1.2.4", got, err)
+	}
+}
@@ -19,61 +19,209 @@ const (
 	Major VersionType = "Major"
 )
 
+// Version is a SemVer 2.0 version. PreRelease and Build hold the dot-separated
+// identifiers without their leading "-" / "+" (e.g. "rc.2", "build.7").
 type Version struct {
-	Major int
-	Minor int
-	Patch int
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease string
+	Build      string
 }
 
 func (v Version) String() string {
-	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
 }
 
-// Parse parses a version string in the format "X.Y.Z"
+// IsPreRelease reports whether v carries pre-release identifiers.
+func (v Version) IsPreRelease() bool {
+	return v.PreRelease != ""
+}
+
+// Parse parses a SemVer 2.0 string: "X.Y.Z[-pre.release][+build.meta]".
+// A single leading "v" is tolerated (e.g. "v1.4.0") and dropped.
 func Parse(versionStr string) (Version, error) {
-	parts := strings.Split(versionStr, ".")
+	s := strings.TrimPrefix(versionStr, "v")
+
+	var build, pre string
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s, build = s[:i], s[i+1:]
+		if err := validateIdentifiers(build, false); err != nil {
+			return Version{}, fmt.Errorf("invalid build metadata in %s: %w", versionStr, err)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, pre = s[:i], s[i+1:]
+		if err := validateIdentifiers(pre, true); err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release in %s: %w", versionStr, err)
+		}
+	}
+
+	parts := strings.Split(s, ".")
 	if len(parts) != 3 {
 		return Version{}, fmt.Errorf("invalid version format: expected X.Y.Z, got %s", versionStr)
 	}
 
-	major, err := strconv.Atoi(parts[0])
+	major, err := parseNumeric(parts[0])
 	if err != nil {
 		return Version{}, fmt.Errorf("invalid major version: %w", err)
 	}
-	minor, err := strconv.Atoi(parts[1])
+	minor, err := parseNumeric(parts[1])
 	if err != nil {
 		return Version{}, fmt.Errorf("invalid minor version: %w", err)
 	}
-	patch, err := strconv.Atoi(parts[2])
+	patch, err := parseNumeric(parts[2])
 	if err != nil {
 		return Version{}, fmt.Errorf("invalid patch version: %w", err)
 	}
 
-	return Version{Major: major, Minor: minor, Patch: patch}, nil
+	return Version{Major: major, Minor: minor, Patch: patch, PreRelease: pre, Build: build}, nil
+}
+
+// parseNumeric parses a core version number; SemVer forbids leading zeros.
+func parseNumeric(s string) (int, error) {
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("leading zero in %q", s)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative number %q", s)
+	}
+	return n, nil
+}
+
+// validateIdentifiers checks dot-separated identifiers: non-empty, [0-9A-Za-z-] only,
+// and (for pre-release) no leading zeros on numeric identifiers.
+func validateIdentifiers(s string, pre bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("empty identifier in %q", s)
+		}
+		for _, r := range id {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return fmt.Errorf("invalid character %q in identifier %q", r, id)
+			}
+		}
+		if pre && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("leading zero in numeric identifier %q", id)
+		}
+	}
+	return nil
 }
 
-// Inc returns a new Version incremented based on the given semantic version type
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Increment returns a new Version incremented based on the given semantic version type.
+// Build metadata is always dropped. A pre-release is promoted rather than skipped:
+// 1.4.0-rc.2 bumped by Patch or Minor becomes 1.4.0, and 2.0.0-rc.1 by Major becomes 2.0.0.
 func (v Version) Increment(bump VersionType) Version {
+	pre := v.IsPreRelease()
 	switch bump {
 	case Major:
+		if pre && v.Minor == 0 && v.Patch == 0 {
+			return Version{Major: v.Major}
+		}
 		return Version{Major: v.Major + 1, Minor: 0, Patch: 0}
 	case Minor:
+		if pre && v.Patch == 0 {
+			return Version{Major: v.Major, Minor: v.Minor}
+		}
 		return Version{Major: v.Major, Minor: v.Minor + 1, Patch: 0}
 	case Patch:
+		if pre {
+			return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+		}
 		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
 	default:
 		return v // no change if invalid bump type
 	}
 }
 
+// Compare returns -1, 0 or +1 following SemVer 2.0 precedence.
+// Build metadata is ignored; a pre-release sorts before its release.
+func (v Version) Compare(other Version) int {
+	if c := cmpInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePreRelease(v.PreRelease, other.PreRelease)
+}
+
 func (v Version) LessThan(other Version) bool {
-	if v.Major != other.Major {
-		return v.Major < other.Major
+	return v.Compare(other) < 0
+}
+
+// comparePreRelease compares pre-release strings per SemVer 2.0 §11:
+// no pre-release > any pre-release; identifiers compare left to right,
+// numeric ones numerically, alphanumeric ones lexically (ASCII), numeric < alphanumeric,
+// and a shorter set of identifiers sorts first when all preceding ones are equal.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
 	}
-	if v.Minor != other.Minor {
-		return v.Minor < other.Minor
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		xn, yn := isNumeric(x), isNumeric(y)
+		switch {
+		case xn && yn:
+			xi, _ := strconv.Atoi(x)
+			yi, _ := strconv.Atoi(y)
+			if c := cmpInt(xi, yi); c != 0 {
+				return c
+			}
+		case xn:
+			return -1
+		case yn:
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
 	}
-	return v.Patch < other.Patch
+	return cmpInt(len(as), len(bs))
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
 }
 
 // ParseVersionType converts a string like "major" into a VersionType enum
@@ -90,42 +238,27 @@ func ParseVersionType(s string) (VersionType, error) {
 	}
 }
 
-// ForecastNext takes the latest tag (e.g., "v1.2.3" or "1.2.3")
+// ForecastNext takes the latest tag (e.g., "v1.2.3", "1.2.3" or "1.2.3-rc.1")
 // and the desired bump, and returns the next version preserving any "v" prefix.
+// Use TagFormat.ForecastNext for other tag conventions.
 func ForecastNext(latestTag string, bump VersionType) (string, error) {
 	latestTag = strings.TrimSpace(latestTag)
 
 	// Preserve prefix style
-	hasV := strings.HasPrefix(latestTag, "v")
-	core := latestTag
-	if hasV {
-		core = strings.TrimPrefix(latestTag, "v")
-	}
-
-	// No latest -> treat as 0.0.0 and bump
-	if core == "" {
-		base := (Version{Major: 0, Minor: 0, Patch: 0}).Increment(bump)
-		if hasV {
-			return "v" + base.String(), nil
-		}
-		return base.String(), nil
+	tmpl := DefaultTagFormat
+	if strings.HasPrefix(latestTag, "v") {
+		tmpl = "v" + DefaultTagFormat
 	}
-
-	// Parse X.Y.Z and bump
-	v, err := Parse(core)
+	f, err := NewTagFormat(tmpl, "")
 	if err != nil {
-		return "", fmt.Errorf("unable to parse latest tag %q: %w", latestTag, err)
-	}
-	next := v.Increment(bump).String()
-	if hasV {
-		return "v" + next, nil
+		return "", err
 	}
-	return next, nil
+	return f.ForecastNext(latestTag, bump)
 }
 
 // ForecastNextRC is like ForecastNext but appends a pre-release identifier.
-// Example: v1.2.4 -> v1.2.4-rc.1
-// If suffix == "", defaults to "rc.1".
+// Example: v1.2.3 -> v1.2.4-rc.1
+// If suffix == "", defaults to "rc.1". The suffix must be valid SemVer pre-release.
 func ForecastNextRC(latestTag string, bump VersionType, suffix string) (string, error) {
 	next, err := ForecastNext(latestTag, bump)
 	if err != nil {
@@ -134,5 +267,52 @@ func ForecastNextRC(latestTag string, bump VersionType, suffix string) (string,
 	if suffix == "" {
 		suffix = "rc.1"
 	}
+	if err := validateIdentifiers(suffix, true); err != nil {
+		return "", fmt.Errorf("invalid pre-release suffix %q: %w", suffix, err)
+	}
 	return fmt.Sprintf("%s-%s", next, suffix), nil
 }
+
+// NextPreRelease allocates the next numbered pre-release of base: given existing
+// versions 1.4.3-rc.1 and 1.4.3-rc.2, NextPreRelease(1.4.3, "rc", existing) is 1.4.3-rc.3.
+// Only versions with the same core and an "<id>.<N>" pre-release are counted;
+// numbering starts at 1.
+func NextPreRelease(base Version, id string, existing []Version) Version {
+	if id == "" {
+		id = "rc"
+	}
+	highest := 0
+	for _, v := range existing {
+		if v.Major != base.Major || v.Minor != base.Minor || v.Patch != base.Patch {
+			continue
+		}
+		rest, ok := strings.CutPrefix(v.PreRelease, id+".")
+		if !ok || !isNumeric(rest) {
+			continue
+		}
+		if n, err := strconv.Atoi(rest); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return Version{
+		Major:      base.Major,
+		Minor:      base.Minor,
+		Patch:      base.Patch,
+		PreRelease: fmt.Sprintf("%s.%d", id, highest+1),
+	}
+}
+
+// Line is a MAJOR.MINOR release line, e.g. 1.4 for a release/1.4 maintenance branch.
+type Line struct {
+	Major int
+	Minor int
+}
+
+func (l Line) String() string {
+	return fmt.Sprintf("%d.%d", l.Major, l.Minor)
+}
+
+// Contains reports whether v belongs to the line (any patch or pre-release).
+func (l Line) Contains(v Version) bool {
+	return v.Major == l.Major && v.Minor == l.Minor
+}
@@ -17,6 +17,46 @@ func TestParse(t *testing.T) {
 			input: "1.2.3",
 			want:  Version{Major: 1, Minor: 2, Patch: 3},
 		},
+		{
+			name:  "Leading v",
+			input: "v1.4.0",
+			want:  Version{Major: 1, Minor: 4},
+		},
+		{
+			name:  "Pre-release",
+			input: "1.4.0-rc.2",
+			want:  Version{Major: 1, Minor: 4, PreRelease: "rc.2"},
+		},
+		{
+			name:  "Build metadata",
+			input: "1.4.0+build.7",
+			want:  Version{Major: 1, Minor: 4, Build: "build.7"},
+		},
+		{
+			name:  "Pre-release and build metadata",
+			input: "1.0.0-alpha-1.beta+exp.sha.5114f85",
+			want:  Version{Major: 1, PreRelease: "alpha-1.beta", Build: "exp.sha.5114f85"},
+		},
+		{
+			name:      "Empty pre-release identifier",
+			input:     "1.2.3-rc..1",
+			expectErr: true,
+		},
+		{
+			name:      "Leading zero in numeric pre-release",
+			input:     "1.2.3-rc.01",
+			expectErr: true,
+		},
+		{
+			name:      "Leading zero in core",
+			input:     "01.2.3",
+			expectErr: true,
+		},
+		{
+			name:      "Invalid build character",
+			input:     "1.2.3+build_7",
+			expectErr: true,
+		},
 		{
 			name:      "Missing patch",
 			input:     "1.2",
@@ -119,16 +159,115 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestStringRoundTrip(t *testing.T) {
+	for _, in := range []string{"1.2.3", "1.4.0-rc.2", "1.4.0+build.7", "2.0.0-alpha.1+sha.deadbeef"} {
+		v, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q) unexpected error: %v", in, err)
+		}
+		if got := v.String(); got != in {
+			t.Errorf("Parse(%q).String() = %q", in, got)
+		}
+	}
+}
+
+func TestIncPreRelease(t *testing.T) {
+	tests := []struct {
+		from string
+		bump VersionType
+		want string
+	}{
+		{"1.4.0-rc.2", Patch, "1.4.0"},
+		{"1.4.0-rc.2", Minor, "1.4.0"},
+		{"1.4.0-rc.2", Major, "2.0.0"},
+		{"1.4.3-rc.1", Minor, "1.5.0"},
+		{"2.0.0-rc.1", Major, "2.0.0"},
+		{"1.2.3+build.7", Patch, "1.2.4"},
+	}
+
+	for _, tt := range tests {
+		v, err := Parse(tt.from)
+		if err != nil {
+			t.Fatalf("Parse(%q) unexpected error: %v", tt.from, err)
+		}
+		if got := v.Increment(tt.bump).String(); got != tt.want {
+			t.Errorf("%s.Increment(%s) = %s; want %s", tt.from, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestComparePrecedence(t *testing.T) {
+	// Ascending order taken from the SemVer 2.0 specification (§11).
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := Parse(ordered[i])
+		b, _ := Parse(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := Parse("1.0.0+build.1")
+	b, _ := Parse("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Errorf("build metadata must not affect precedence")
+	}
+}
+
+func TestForecastNextRC(t *testing.T) {
+	tests := []struct {
+		latest string
+		bump   VersionType
+		suffix string
+		want   string
+		err    bool
+	}{
+		{"1.2.3", Patch, "", "1.2.4-rc.1", false},
+		{"v1.2.3", Minor, "rc.3", "v1.3.0-rc.3", false},
+		{"1.4.0-rc.2", Patch, "rc.3", "1.4.0-rc.3", false},
+		{"", Patch, "rc.1", "0.0.1-rc.1", false},
+		{"1.2.3", Patch, "rc..1", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ForecastNextRC(tt.latest, tt.bump, tt.suffix)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ForecastNextRC(%q, %s, %q) expected error", tt.latest, tt.bump, tt.suffix)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ForecastNextRC(%q, %s, %q) = %q, %v; want %q", tt.latest, tt.bump, tt.suffix, got, err, tt.want)
+		}
+	}
+}
+
 func TestLessThan(t *testing.T) {
 	tests := []struct {
 		a, b Version
 		want bool
 	}{
-		{Version{1, 0, 0}, Version{1, 0, 1}, true},
-		{Version{1, 2, 0}, Version{1, 3, 0}, true},
-		{Version{1, 2, 3}, Version{2, 0, 0}, true},
-		{Version{2, 0, 0}, Version{1, 2, 3}, false},
-		{Version{1, 2, 3}, Version{1, 2, 3}, false},
+		{Version{Major: 1}, Version{Major: 1, Patch: 1}, true},
+		{Version{Major: 1, Minor: 2}, Version{Major: 1, Minor: 3}, true},
+		{Version{Major: 1, Minor: 2, Patch: 3}, Version{Major: 2}, true},
+		{Version{Major: 2}, Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{Version{Major: 1, Minor: 2, Patch: 3}, Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{Version{Major: 1, PreRelease: "rc.1"}, Version{Major: 1}, true},
+		{Version{Major: 1}, Version{Major: 1, PreRelease: "rc.1"}, false},
 	}
 
 	for _, tt := range tests {
@@ -181,3 +320,45 @@ func TestVersionTypeString(t *testing.T) {
 		}
 	}
 }
+
+func TestNextPreRelease(t *testing.T) {
+	base := Version{Major: 1, Minor: 4, Patch: 3}
+	existing := []Version{
+		{Major: 1, Minor: 4, Patch: 3, PreRelease: "rc.1"},
+		{Major: 1, Minor: 4, Patch: 3, PreRelease: "rc.10"},
+		{Major: 1, Minor: 4, Patch: 3, PreRelease: "rc.2"},
+		{Major: 1, Minor: 4, Patch: 3, PreRelease: "beta.40"},
+		{Major: 1, Minor: 4, Patch: 2, PreRelease: "rc.99"},
+		{Major: 1, Minor: 4, Patch: 3, PreRelease: "rc.x"},
+	}
+
+	if got := NextPreRelease(base, "rc", existing).String(); got != "1.4.3-rc.11" {
+		t.Errorf("NextPreRelease = %s; want 1.4.3-rc.11", got)
+	}
+	if got := NextPreRelease(base, "rc", nil).String(); got != "1.4.3-rc.1" {
+		t.Errorf("NextPreRelease with no tags = %s; want 1.4.3-rc.1", got)
+	}
+	if got := NextPreRelease(base, "beta", existing).String(); got != "1.4.3-beta.41" {
+		t.Errorf("NextPreRelease(beta) = %s; want 1.4.3-beta.41", got)
+	}
+}
+
+func TestLineContains(t *testing.T) {
+	line := Line{Major: 1, Minor: 4}
+	if line.String() != "1.4" {
+		t.Errorf("Line.String() = %q; want 1.4", line.String())
+	}
+	for _, tc := range []struct {
+		v    Version
+		want bool
+	}{
+		{Version{Major: 1, Minor: 4, Patch: 7}, true},
+		{Version{Major: 1, Minor: 4, PreRelease: "rc.1"}, true},
+		{Version{Major: 1, Minor: 5}, false},
+		{Version{Major: 2, Minor: 4}, false},
+	} {
+		if got := line.Contains(tc.v); got != tc.want {
+			t.Errorf("Line(1.4).Contains(%s) = %v; want %v", tc.v, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package versionfiles
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Updater rewrites the version inside one file's content.
+// Implementations must leave everything else in the file untouched.
+type Updater interface {
+	Update(content []byte, version string) ([]byte, error)
+}
+
+// TextUpdater treats the whole file as the version (e.g. VERSION).
+type TextUpdater struct{}
+
+func (TextUpdater) Update(content []byte, version string) ([]byte, error) {
+	return []byte(version + "\n"), nil
+}
+
+// JSONUpdater replaces the string at a dot-separated object key path
+// (e.g. "version" in package.json) while preserving formatting and key order.
+type JSONUpdater struct {
+	Path string
+}
+
+func (u JSONUpdater) Update(content []byte, version string) ([]byte, error) {
+	keys := strings.Split(u.Path, ".")
+	start, end, err := findJSONValue(content, keys)
+	if err != nil {
+		return nil, fmt.Errorf("json %s: %w", u.Path, err)
+	}
+	quoted, _ := json.Marshal(version)
+
+	out := make([]byte, 0, len(content)+len(quoted))
+	out = append(out, content[:start]...)
+	out = append(out, quoted...)
+	return append(out, content[end:]...), nil
+}
+
+// jsonFrame is one open object/array while walking tokens.
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	key       string
+	keyEnd    int64
+}
+
+// findJSONValue returns the byte range of the scalar value at keys.
+func findJSONValue(content []byte, keys []string) (int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var stack []*jsonFrame
+
+	matches := func() bool {
+		if len(stack) != len(keys) {
+			return false
+		}
+		for i, f := range stack {
+			if !f.object || f.key != keys[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return 0, 0, errors.New("key not found")
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				if top != nil && top.object {
+					if matches() {
+						return 0, 0, errors.New("value is not a scalar")
+					}
+					top.expectKey = true
+				}
+				stack = append(stack, &jsonFrame{object: d == '{', expectKey: d == '{'})
+			default:
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		if top != nil && top.object && top.expectKey {
+			top.key, _ = tok.(string)
+			top.keyEnd = dec.InputOffset()
+			top.expectKey = false
+			continue
+		}
+
+		if matches() {
+			// Value starts after the ':' following the key.
+			i := int(top.keyEnd)
+			for i < len(content) && content[i] != ':' {
+				i++
+			}
+			i++
+			for i < len(content) && strings.ContainsRune(" \t\r\n", rune(content[i])) {
+				i++
+			}
+			return i, int(dec.InputOffset()), nil
+		}
+		if top != nil && top.object {
+			top.expectKey = true
+		}
+	}
+}
+
+// YAMLUpdater replaces the scalar at a dot-separated mapping key path
+// (e.g. "appVersion" in Chart.yaml, "image.tag" in values.yaml). It edits the
+// line in place, keeping quotes and trailing comments. Sequences and
+// multi-line scalars are not supported.
+type YAMLUpdater struct {
+	Key string
+}
+
+var yamlKeyRe = regexp.MustCompile(`^(\s*)([A-Za-z0-9_.\-]+|"[^"]*"|'[^']*')(\s*:)(\s*)(.*)$`)
+
+func (u YAMLUpdater) Update(content []byte, version string) ([]byte, error) {
+	keys := strings.Split(u.Key, ".")
+	lines := strings.Split(string(content), "\n")
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var path []level
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		m := yamlKeyRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := len(m[1])
+		key := strings.Trim(m[2], `"'`)
+		for len(path) > 0 && path[len(path)-1].indent >= indent {
+			path = path[:len(path)-1]
+		}
+		path = append(path, level{indent: indent, key: key})
+
+		if len(path) != len(keys) {
+			continue
+		}
+		match := true
+		for j, l := range path {
+			if l.key != keys[j] {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+
+		value, comment := splitYAMLComment(m[5])
+		if value == "" {
+			return nil, fmt.Errorf("yaml %s: value is not a scalar", u.Key)
+		}
+		switch value[0] {
+		case '"':
+			value = strconv.Quote(version)
+		case '\'':
+			value = "'" + version + "'"
+		default:
+			value = version
+		}
+		lines[i] = m[1] + m[2] + m[3] + m[4] + value + comment
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+	return nil, fmt.Errorf("yaml %s: key not found", u.Key)
+}
+
+// splitYAMLComment splits "1.2.3  # pinned" into ("1.2.3", "  # pinned").
+func splitYAMLComment(s string) (string, string) {
+	inSingle, inDouble := false, false
+	for i, r := range s {
+		switch {
+		case r == '\'' && !inDouble:
+			inSingle = !inSingle
+		case r == '"' && !inSingle:
+			inDouble = !inDouble
+		case r == '#' && !inSingle && !inDouble && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			value := strings.TrimRight(s[:i], " \t")
+			return value, s[len(value):]
+		}
+	}
+	return strings.TrimRight(s, " \t\r"), s[len(strings.TrimRight(s, " \t\r")):]
+}
+
+// RegexUpdater replaces the first capture group of the first match, e.g.
+// `<artifactId>app</artifactId>\s*<version>([^<]+)</version>` for pom.xml.
+type RegexUpdater struct {
+	Pattern *regexp.Regexp
+}
+
+func (u RegexUpdater) Update(content []byte, version string) ([]byte, error) {
+	loc := u.Pattern.FindSubmatchIndex(content)
+	if loc == nil || len(loc) < 4 || loc[2] < 0 {
+		return nil, fmt.Errorf("regex %s: no match", u.Pattern)
+	}
+	out := make([]byte, 0, len(content)+len(version))
+	out = append(out, content[:loc[2]]...)
+	out = append(out, version...)
+	return append(out, content[loc[3]:]...), nil
+}
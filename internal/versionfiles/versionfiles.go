@@ -0,0 +1,184 @@
+// Package versionfiles keeps in-repo version files (VERSION, package.json,
+// Chart.yaml, pom.xml, ...) in sync with the version syac computed.
+package versionfiles
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// File is one configured version file and the updater that rewrites it.
+type File struct {
+	Path    string
+	Kind    string // text | json | yaml | regex
+	Updater Updater
+}
+
+// Change is the result of updating one file.
+type Change struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// Changed reports whether the update modified the file.
+func (c Change) Changed() bool {
+	return string(c.Old) != string(c.New)
+}
+
+// ParseConfig reads a SYAC_VERSION_FILES value. Entries are separated by
+// newlines (or ";" on a single line) and look like <path>[:<kind>[:<arg>]]:
+//
+//	VERSION
+//	package.json:json:version
+//	chart/Chart.yaml:yaml:appVersion
+//	pom.xml:regex:<artifactId>app</artifactId>\s*<version>([^<]+)</version>
+//
+// Without a kind, VERSION/*.txt are text, *.json is json:version and
+// *.yaml/*.yml is yaml:version.
+func ParseConfig(raw string) ([]File, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	sep := "\n"
+	if !strings.Contains(raw, "\n") {
+		sep = ";"
+	}
+
+	var files []File
+	for _, entry := range strings.Split(raw, sep) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		f, err := parseEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("SYAC_VERSION_FILES: %w", err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func parseEntry(entry string) (File, error) {
+	p, rest, _ := strings.Cut(entry, ":")
+	kind, arg, _ := strings.Cut(rest, ":")
+	p = strings.TrimSpace(p)
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if p == "" {
+		return File{}, fmt.Errorf("entry %q has no path", entry)
+	}
+
+	if kind == "" {
+		switch ext := strings.ToLower(path.Ext(p)); {
+		case path.Base(p) == "VERSION" || ext == ".txt":
+			kind = "text"
+		case ext == ".json":
+			kind, arg = "json", "version"
+		case ext == ".yaml" || ext == ".yml":
+			kind, arg = "yaml", "version"
+		default:
+			return File{}, fmt.Errorf("%s: no default updater; use %s:<text|json|yaml|regex>[:<arg>]", p, p)
+		}
+	}
+
+	f := File{Path: p, Kind: kind}
+	switch kind {
+	case "text":
+		f.Updater = TextUpdater{}
+	case "json":
+		if arg == "" {
+			return File{}, fmt.Errorf("%s: json updater needs a key path", p)
+		}
+		f.Updater = JSONUpdater{Path: arg}
+	case "yaml":
+		if arg == "" {
+			return File{}, fmt.Errorf("%s: yaml updater needs a key path", p)
+		}
+		f.Updater = YAMLUpdater{Key: arg}
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return File{}, fmt.Errorf("%s: %w", p, err)
+		}
+		if re.NumSubexp() < 1 {
+			return File{}, fmt.Errorf("%s: regex must capture the version in group 1", p)
+		}
+		f.Updater = RegexUpdater{Pattern: re}
+	default:
+		return File{}, fmt.Errorf("%s: unknown updater %q (text, json, yaml, regex)", p, kind)
+	}
+	return f, nil
+}
+
+// Apply reads each file from dir, rewrites it with version and returns the
+// changes. Nothing is written; see Write.
+func Apply(dir string, files []File, version string) ([]Change, error) {
+	changes := make([]Change, 0, len(files))
+	for _, f := range files {
+		old, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+		updated, err := f.Updater.Update(old, version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+		changes = append(changes, Change{Path: f.Path, Old: old, New: updated})
+	}
+	return changes, nil
+}
+
+// Write stores changed files back to dir.
+func Write(dir string, changes []Change) error {
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+		full := filepath.Join(dir, filepath.FromSlash(c.Path))
+		st, err := os.Stat(full)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Path, err)
+		}
+		if err := os.WriteFile(full, c.New, st.Mode().Perm()); err != nil {
+			return fmt.Errorf("%s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+// Diff renders a compact unified-style diff of the changed lines.
+func Diff(c Change) string {
+	if !c.Changed() {
+		return ""
+	}
+	oldLines := strings.Split(string(c.Old), "\n")
+	newLines := strings.Split(string(c.New), "\n")
+
+	// Trim the common prefix/suffix; version edits are local.
+	start := 0
+	for start < len(oldLines) && start < len(newLines) && oldLines[start] == newLines[start] {
+		start++
+	}
+	oe, ne := len(oldLines), len(newLines)
+	for oe > start && ne > start && oldLines[oe-1] == newLines[ne-1] {
+		oe--
+		ne--
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", c.Path, c.Path)
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, oe-start, start+1, ne-start)
+	for _, l := range oldLines[start:oe] {
+		b.WriteString("-" + l + "\n")
+	}
+	for _, l := range newLines[start:ne] {
+		b.WriteString("+" + l + "\n")
+	}
+	return b.String()
+}
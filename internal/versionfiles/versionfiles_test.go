@@ -0,0 +1,139 @@
+package versionfiles
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestJSONUpdater(t *testing.T) {
+	in := `{
+  "name": "app",
+  "version": "1.2.3",
+  "nested": {"version": "9.9.9", "list": [1, {"version": "x"}]},
+  "scripts": {}
+}
+`
+	out, err := JSONUpdater{Path: "version"}.Update([]byte(in), "1.3.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.Replace(in, `"version": "1.2.3"`, `"version": "1.3.0"`, 1)
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = JSONUpdater{Path: "nested.version"}.Update([]byte(in), "2.0.0")
+	if err != nil || !strings.Contains(string(out), `{"version": "2.0.0", "list"`) {
+		t.Errorf("nested update = %s, %v", out, err)
+	}
+
+	if _, err := (JSONUpdater{Path: "missing"}).Update([]byte(in), "1.0.0"); err == nil {
+		t.Errorf("expected error for missing key")
+	}
+	if _, err := (JSONUpdater{Path: "scripts"}).Update([]byte(in), "1.0.0"); err == nil {
+		t.Errorf("expected error for non-scalar value")
+	}
+}
+
+func TestYAMLUpdater(t *testing.T) {
+	in := `apiVersion: v2
+name: app
+version: 0.1.0 # chart version
+appVersion: "1.2.3"
+image:
+  repository: reg/app
+  tag: '1.2.3'
+`
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"version", "version: 1.3.0 # chart version"},
+		{"appVersion", `appVersion: "1.3.0"`},
+		{"image.tag", "  tag: '1.3.0'"},
+	}
+	for _, tt := range tests {
+		out, err := YAMLUpdater{Key: tt.key}.Update([]byte(in), "1.3.0")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.key, err)
+		}
+		if !strings.Contains(string(out), tt.want+"\n") {
+			t.Errorf("%s: output missing %q:\n%s", tt.key, tt.want, out)
+		}
+		if strings.Count(string(out), "1.3.0") != 1 {
+			t.Errorf("%s: expected exactly one replacement:\n%s", tt.key, out)
+		}
+	}
+	if _, err := (YAMLUpdater{Key: "tag"}).Update([]byte(in), "1.3.0"); err == nil {
+		t.Errorf("expected error: nested key must not match at top level")
+	}
+}
+
+func TestRegexUpdater(t *testing.T) {
+	in := `<project>
+  <parent><version>5.0.0</version></parent>
+  <artifactId>app</artifactId>
+  <version>1.2.3</version>
+</project>`
+	re := regexp.MustCompile(`<artifactId>app</artifactId>\s*<version>([^<]+)</version>`)
+	out, err := RegexUpdater{Pattern: re}.Update([]byte(in), "1.3.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "<version>1.3.0</version>") || !strings.Contains(string(out), "<version>5.0.0</version>") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	files, err := ParseConfig("VERSION;package.json;chart/Chart.yaml:yaml:appVersion;pom.xml:regex:<version>([^<]+)</version>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kinds := []string{"text", "json", "yaml", "regex"}
+	if len(files) != len(kinds) {
+		t.Fatalf("got %d files; want %d", len(files), len(kinds))
+	}
+	for i, f := range files {
+		if f.Kind != kinds[i] {
+			t.Errorf("%s: kind %q; want %q", f.Path, f.Kind, kinds[i])
+		}
+	}
+
+	for _, bad := range []string{"pom.xml", "a.json:json:", "x:regex:no-group", "x:toml:version"} {
+		if _, err := ParseConfig(bad); err == nil {
+			t.Errorf("ParseConfig(%q) expected error", bad)
+		}
+	}
+}
+
+func TestApplyWriteDiff(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.2.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := ParseConfig("VERSION")
+
+	changes, err := Apply(dir, files, "1.3.0")
+	if err != nil || len(changes) != 1 || !changes[0].Changed() {
+		t.Fatalf("Apply = %+v, %v", changes, err)
+	}
+	if d := Diff(changes[0]); !strings.Contains(d, "-1.2.3\n+1.3.0\n") {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+	if err := Write(dir, changes); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if string(got) != "1.3.0\n" {
+		t.Errorf("VERSION = %q", got)
+	}
+
+	again, _ := Apply(dir, files, "1.3.0")
+	if again[0].Changed() {
+		t.Errorf("re-applying the same version should be a no-op")
+	}
+}
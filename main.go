@@ -6,11 +6,14 @@
 // builds/pushes Docker images accordingly.
 //
 // Keep this file simple: load context, annotate (best-effort), print summary,
-// resolve flow, build options, build/push. All the heavy lifting stays internal.
+// resolve flow, build options, build/push, tag the RC, release, refresh the MR
+// note. All the heavy lifting stays internal. Monorepos (SYAC_COMPONENTS) repeat
+// the per-build steps once per affected component and share one MR note.
 
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -25,23 +28,79 @@ func main() {
 	// Local overrides for dev runs; harmless in CI.
 	_ = godotenv.Load("environments/mr.env")
 
-	// 1) CI/CD runtime context
-	ctx, err := runtime.LoadContext()
+	// 1) Per-run deadline + job cancellation, applied to every GitLab API call
+	// and docker command.
+	runCtx, cancel, err := runtime.RunContext()
 	if err != nil {
-		log.Fatalf("failed to load context: %v", err)
+		log.Fatalf("failed to set up run context: %v", err)
 	}
+	defer cancel()
 
 	// 2) GitLab client
 	client, err := gitlab.NewClient()
 	if err != nil {
 		log.Printf("[gitlab] init failed; skipping MR annotate + release lookup: %v", err)
-	} else {
-		// 2a) Early, best-effort MR annotate (idempotent). Non-blocking by design.
-		runtime.UpsertMRDescriptionIfNeeded(client, &ctx, log.Printf)
 	}
 
-	// 3) Print summary (does MR bump resolution + version forecast)
-	(&ctx).PrintSummary(client) // safe with nil client; guards inside
+	// 2a) CI/CD runtime context (+ commit-message directives; safe with nil client)
+	ctx, err := runtime.LoadContext(runCtx, client)
+	if err != nil {
+		log.Fatalf("failed to load context: %v", err)
+	}
+
+	// 2b) Early, best-effort MR annotate (idempotent). Non-blocking by design.
+	runtime.UpsertMRDescriptionIfNeeded(runCtx, client, &ctx, log.Printf) // safe with nil client
+
+	// 2c) Monorepo: one version stream + image per affected component.
+	components, err := runtime.LoadComponents()
+	if err != nil {
+		log.Fatalf("failed to load components: %v", err)
+	}
+	if len(components) == 0 {
+		result := build(runCtx, client, ctx)
+		// 10) MR release-type note with the forecast + pushed refs (best-effort).
+		runtime.UpsertMRCommentIfNeeded(runCtx, client, []runtime.BuildResult{result}, log.Printf) // safe with nil client
+		return
+	}
+
+	affected := runtime.AffectedComponents(runCtx, client, &ctx, components, log.Printf)
+	if len(affected) == 0 {
+		log.Printf("[components] no affected components; nothing to do")
+		return
+	}
+	var results []runtime.BuildResult
+	for _, comp := range affected {
+		cctx, err := ctx.ForComponent(comp)
+		if err != nil {
+			log.Fatalf("failed to prepare component %s: %v", comp.Name, err)
+		}
+		log.Printf("[components] ===== %s =====", comp.Name)
+		results = append(results, build(runCtx, client, cctx))
+	}
+
+	// 10) One MR release-type note for every component built (best-effort).
+	runtime.UpsertMRCommentIfNeeded(runCtx, client, results, log.Printf) // safe with nil client
+}
+
+// build runs steps 3–9 for one application (or one monorepo component) and
+// returns what the MR note (step 10) needs.
+func build(runCtx context.Context, client *gitlab.Client, ctx runtime.Context) runtime.BuildResult {
+	// 3) Print summary (does MR bump resolution + version forecast).
+	// Safe with nil client; guardrail violations fail the job before building.
+	err := (&ctx).PrintSummary(runCtx, client)
+
+	// 3a) Warn on the MR about conflicting release-type selections and mirror the
+	// selection to its label (best-effort), before a conflict guardrail fails the job.
+	runtime.ReportBumpConflictIfNeeded(runCtx, client, &ctx, log.Printf)
+	runtime.SyncBumpLabelIfNeeded(runCtx, client, &ctx, log.Printf)
+	if err != nil {
+		log.Fatalf("refusing to continue: %v", err)
+	}
+
+	// 3b) Sync in-repo version files (VERSION, package.json, ...) before building.
+	if err := runtime.SyncVersionFilesIfNeeded(runCtx, client, &ctx, log.Printf); err != nil {
+		log.Fatalf("version file sync failed: %v", err)
+	}
 
 	// 4) Resolve flow → tags/push policy are derived from it
 	flow := runtime.ResolveFlow(ctx, runtime.FlowAuto)
@@ -69,7 +128,21 @@ func main() {
 	)
 
 	// 7) Build (and push if enabled). Honors dry-run.
-	if err := docker.BuildAndPush(opts); err != nil {
+	if err := docker.BuildAndPush(runCtx, opts); err != nil {
 		log.Fatalf("build/push failed: %v", err)
 	}
+
+	// 8) Reserve the RC number on default-branch builds (opt-in, best-effort).
+	runtime.CreateRCTagIfNeeded(runCtx, client, &ctx, log.Printf) // safe with nil client
+
+	// 9) Release: version tag + GitLab Release for the merged MR (opt-in).
+	if err := runtime.ReleaseIfNeeded(runCtx, client, &ctx, log.Printf); err != nil {
+		log.Fatalf("release failed: %v", err)
+	}
+
+	result := runtime.BuildResult{Context: ctx}
+	if opts.Push {
+		result.Pushed = opts.FullRefs
+	}
+	return result
 }
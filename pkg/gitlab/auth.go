@@ -0,0 +1,128 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Authenticator sets credentials on an outgoing API request. A client holds an
+// ordered list of them and falls back to the next one when GitLab answers
+// 401/403 (e.g. an endpoint that doesn't accept JOB-TOKEN).
+type Authenticator interface {
+	Name() string
+	Authenticate(req *http.Request) error
+}
+
+// errAuthUnavailable marks an authenticator that couldn't produce credentials
+// (e.g. an unreadable token file); the client moves on to the next one.
+var errAuthUnavailable = errors.New("credentials unavailable")
+
+// AuthScheme is how a token is sent to GitLab.
+type AuthScheme string
+
+const (
+	AuthPrivateToken AuthScheme = "private-token" // PRIVATE-TOKEN: personal/project/group access tokens
+	AuthJobToken     AuthScheme = "job-token"     // JOB-TOKEN: CI_JOB_TOKEN, limited set of endpoints
+	AuthBearer       AuthScheme = "bearer"        // Authorization: Bearer, OAuth or ID-token-exchanged tokens
+)
+
+// setToken applies token to req according to scheme.
+func (s AuthScheme) setToken(req *http.Request, token string) error {
+	switch s {
+	case AuthPrivateToken:
+		req.Header.Set("PRIVATE-TOKEN", token)
+	case AuthJobToken:
+		req.Header.Set("JOB-TOKEN", token)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("unknown auth scheme %q", s)
+	}
+	return nil
+}
+
+type tokenAuth struct {
+	scheme AuthScheme
+	token  string
+}
+
+// PrivateToken authenticates with a personal/project/group access token.
+func PrivateToken(token string) Authenticator { return tokenAuth{AuthPrivateToken, token} }
+
+// JobToken authenticates with a CI job token (CI_JOB_TOKEN).
+func JobToken(token string) Authenticator { return tokenAuth{AuthJobToken, token} }
+
+// BearerToken authenticates with an OAuth (or ID-token-exchanged) access token.
+func BearerToken(token string) Authenticator { return tokenAuth{AuthBearer, token} }
+
+func (a tokenAuth) Name() string { return string(a.scheme) }
+
+func (a tokenAuth) Authenticate(req *http.Request) error {
+	return a.scheme.setToken(req, a.token)
+}
+
+type fileAuth struct {
+	scheme AuthScheme
+	path   string
+}
+
+// TokenFile authenticates with a token read from path on every request, so a
+// token rotated on disk (e.g. by a secrets agent) is picked up without a restart.
+func TokenFile(scheme AuthScheme, path string) Authenticator { return fileAuth{scheme, path} }
+
+func (a fileAuth) Name() string { return fmt.Sprintf("%s (file %s)", a.scheme, a.path) }
+
+func (a fileAuth) Authenticate(req *http.Request) error {
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return fmt.Errorf("read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("token file %s is empty", a.path)
+	}
+	return a.scheme.setToken(req, token)
+}
+
+// authFromEnv builds the authenticator chain from the environment, in order:
+//   - SYAC_GITLAB_API_TOKEN or GITLAB_API_TOKEN (PRIVATE-TOKEN)
+//   - SYAC_GITLAB_API_TOKEN_FILE (PRIVATE-TOKEN read from a file)
+//   - SYAC_GITLAB_OAUTH_TOKEN (Bearer)
+//   - SYAC_GITLAB_OAUTH_TOKEN_FILE (Bearer read from a file)
+//   - CI_JOB_TOKEN (JOB-TOKEN), unless SYAC_GITLAB_USE_JOB_TOKEN=false
+func authFromEnv() []Authenticator {
+	var auths []Authenticator
+	if t := firstEnv("SYAC_GITLAB_API_TOKEN", "GITLAB_API_TOKEN"); t != "" {
+		auths = append(auths, PrivateToken(t))
+	}
+	if p := strings.TrimSpace(os.Getenv("SYAC_GITLAB_API_TOKEN_FILE")); p != "" {
+		auths = append(auths, TokenFile(AuthPrivateToken, p))
+	}
+	if t := strings.TrimSpace(os.Getenv("SYAC_GITLAB_OAUTH_TOKEN")); t != "" {
+		auths = append(auths, BearerToken(t))
+	}
+	if p := strings.TrimSpace(os.Getenv("SYAC_GITLAB_OAUTH_TOKEN_FILE")); p != "" {
+		auths = append(auths, TokenFile(AuthBearer, p))
+	}
+	if t := strings.TrimSpace(os.Getenv("CI_JOB_TOKEN")); t != "" && os.Getenv("SYAC_GITLAB_USE_JOB_TOKEN") != "false" {
+		auths = append(auths, JobToken(t))
+	}
+	return auths
+}
+
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// isAuthRejected reports a 401/403, i.e. a reason to try the next authenticator.
+func isAuthRejected(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
@@ -0,0 +1,65 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthFallsBackOnRejection(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("oauth-from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("JOB-TOKEN") != "":
+			seen = append(seen, "job")
+			http.Error(w, "job token not allowed here", http.StatusForbidden)
+		case r.Header.Get("PRIVATE-TOKEN") != "":
+			seen = append(seen, "pat")
+			http.Error(w, "revoked", http.StatusUnauthorized)
+		case r.Header.Get("Authorization") == "Bearer oauth-from-file":
+			seen = append(seen, "bearer")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+	})
+	c.auth = []Authenticator{
+		JobToken("job"),
+		TokenFile(AuthPrivateToken, filepath.Join(t.TempDir(), "missing")), // skipped, unreadable
+		PrivateToken("pat"),
+		TokenFile(AuthBearer, tokenFile),
+	}
+	c.logger = t.Logf
+
+	if _, err := c.DoRequest("GET", "/projects/1", nil); err != nil {
+		t.Fatalf("DoRequest: %v", err)
+	}
+	if fmt.Sprint(seen) != "[job pat bearer]" {
+		t.Fatalf("auth order = %v, want [job pat bearer]", seen)
+	}
+}
+
+func TestAuthAllRejected(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "nope", http.StatusUnauthorized)
+	})
+	c.auth = []Authenticator{PrivateToken("a"), BearerToken("b")}
+	c.logger = t.Logf
+
+	_, err := c.DoRequest("GET", "/projects/1", nil)
+	if gerr, ok := err.(*GitLabError); !ok || gerr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want 401 GitLabError", err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 (one per authenticator, no retries)", calls)
+	}
+}
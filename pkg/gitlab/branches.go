@@ -1,13 +1,14 @@
 package gitlab
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
 // BranchesService defines the interface for GitLab branch operations.
 type BranchesService interface {
 	ListProtectedBranches() ([]ProtectedBranch, error)
+	ListProtectedBranchesCtx(ctx context.Context) ([]ProtectedBranch, error)
 }
 
 // branchesService is a concrete implementation of BranchesService.
@@ -15,18 +16,17 @@ type branchesService struct {
 	client *Client
 }
 
-// ListProtectedBranches fetches all protected branches from the project.
+// ListProtectedBranches fetches all protected branches from the project, across all pages.
 func (s *branchesService) ListProtectedBranches() ([]ProtectedBranch, error) {
+	return s.ListProtectedBranchesCtx(context.Background())
+}
+
+// ListProtectedBranchesCtx is ListProtectedBranches under an explicit context.
+func (s *branchesService) ListProtectedBranchesCtx(ctx context.Context) ([]ProtectedBranch, error) {
 	path := fmt.Sprintf("/projects/%s/protected_branches", urlEncode(s.client.projectID))
-	respData, err := s.client.DoRequest("GET", path, nil)
+	branches, err := ListAllCtx[ProtectedBranch](ctx, s.client, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch protected branches: %w", err)
 	}
-
-	var branches []ProtectedBranch
-	if err := json.Unmarshal(respData, &branches); err != nil {
-		return nil, fmt.Errorf("failed to parse protected branches list: %w", err)
-	}
-
 	return branches, nil
 }
@@ -2,25 +2,36 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"syac/internal/transport"
 )
 
 type Client struct {
 	baseURL    string
-	token      string
+	auth       []Authenticator // tried in order; next one on 401/403
 	httpClient *http.Client
 	projectID  string
+	retry      RetryPolicy
+	timeout    time.Duration // WithTimeout; applied to httpClient by New
+	userAgent  string
+
+	logger  func(string, ...any) // retry logging; log.Printf when nil
+	sleepFn func(time.Duration)  // retry waits; time.Sleep when nil
 
-	// Services
+	// Services. Every API method has a ...Ctx variant taking a context first
+	// (cancellation, deadlines); the plain form runs under context.Background().
 	MergeRequests MergeRequestsService
 	Tags          TagsService
 	Commits       CommitsService
@@ -34,6 +45,7 @@ type GitLabError struct {
 	StatusCode int
 	Message    string
 	Body       []byte
+	Header     http.Header // response headers (Retry-After, RateLimit-*)
 }
 
 // Error returns a string representation of the GitLabError.
@@ -42,24 +54,25 @@ func (e *GitLabError) Error() string {
 }
 
 // NewClient creates a new GitLab client using environment variables for configuration.
-// It expects a Personal Access Token or Project Access Token.
+// It is a convenience wrapper around New for CI; Go tools should prefer New.
 // Required environment variables:
-//   - SYAC_GITLAB_API_TOKEN (preferred) or GITLAB_API_TOKEN
+//   - at least one credential: SYAC_GITLAB_API_TOKEN (preferred) or GITLAB_API_TOKEN,
+//     a token file, an OAuth token, or CI_JOB_TOKEN (see authFromEnv)
 //   - CI_API_V4_URL (if running in CI) or GITLAB_BASE_URL (if running locally)
 //   - CI_PROJECT_ID (if running in CI) or GITLAB_PROJECT_ID (if running locally)
 //
 // An optional GITLAB_CLIENT_TIMEOUT_SECONDS can be set to configure the HTTP client timeout.
+// CA bundles, client certificates and proxies come from SYAC_CA_FILE and friends
+// (see transport.FromEnv).
+// Retries of transient failures are tuned with GITLAB_CLIENT_MAX_RETRIES and friends
+// (see retryPolicyFromEnv).
 func NewClient() (*Client, error) {
-	var baseURL, token, projectID string
+	var baseURL, projectID string
 
 	isPipeline := os.Getenv("GITLAB_CI") == "true"
 
-	// Prioritize SYAC_GITLAB_API_TOKEN
-	token = os.Getenv("SYAC_GITLAB_API_TOKEN")
-	if token == "" {
-		// Fallback to GITLAB_API_TOKEN
-		token = os.Getenv("GITLAB_API_TOKEN")
-	}
+	// Credentials, in fallback order (PAT first).
+	auth := authFromEnv()
 
 	if isPipeline {
 		baseURL = strings.TrimSuffix(os.Getenv("CI_API_V4_URL"), "/api/v4")
@@ -69,90 +82,200 @@ func NewClient() (*Client, error) {
 		projectID = os.Getenv("GITLAB_PROJECT_ID")
 	}
 
-	if token == "" {
-		return nil, errors.New("SYAC_GITLAB_API_TOKEN or GITLAB_API_TOKEN must be set")
+	if len(auth) == 0 {
+		return nil, errors.New("SYAC_GITLAB_API_TOKEN or GITLAB_API_TOKEN (or a token file, OAuth token or CI_JOB_TOKEN) must be set")
 	}
 
 	if projectID == "" {
 		return nil, errors.New("CI_PROJECT_ID or GITLAB_PROJECT_ID must be set")
 	}
 
-	if _, err := url.ParseRequestURI(baseURL); err != nil {
-		return nil, errors.New("invalid GitLab base URL: " + err.Error())
+	opts := []Option{
+		WithAuth(auth...),
+		WithProject(projectID),
+		WithRetryPolicy(retryPolicyFromEnv(DefaultRetryPolicy)),
+	}
+
+	// Custom CA bundle, mTLS, proxy (see transport.FromEnv)
+	if tc := transport.FromEnv(); !tc.IsZero() {
+		hc, err := tc.HTTPClient(defaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithHTTPClient(hc))
 	}
 
 	// Optional timeout override
-	timeout := 10 * time.Second
 	if timeoutStr := os.Getenv("GITLAB_CLIENT_TIMEOUT_SECONDS"); timeoutStr != "" {
 		if seconds, err := strconv.Atoi(timeoutStr); err == nil && seconds > 0 {
-			timeout = time.Duration(seconds) * time.Second
+			opts = append(opts, WithTimeout(time.Duration(seconds)*time.Second))
 		}
 	}
 
-	c := &Client{
-		baseURL: strings.TrimRight(baseURL, "/"),
-		token:   token,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
-		projectID: projectID,
-	}
+	return New(baseURL, opts...)
+}
 
-	// Initialize services
+// initServices (re)binds every service to c.
+func (c *Client) initServices() {
 	c.MergeRequests = &mrsService{client: c}
 	c.Tags = &tagsService{client: c}
 	c.Commits = &commitsService{client: c}
 	c.Releases = &releasesService{client: c}
 	c.Branches = &branchesService{client: c}
 	c.Repositories = &repoFilesService{client: c}
+}
+
+// Project returns a shallow copy of the client whose services target another
+// project (numeric ID or "group/project" path), e.g. to tag a deploy repo
+// while releasing a service:
+//
+//	deploy := client.Project("group/deploy")
+//	err := deploy.Tags.CreateTag("app-1.4.0", "main", "")
+func (c *Client) Project(project string) *Client {
+	cc := *c
+	cc.projectID = project
+	cc.initServices()
+	return &cc
+}
 
-	return c, nil
+// ProjectID returns the project the client's services target.
+func (c *Client) ProjectID() string {
+	return c.projectID
 }
 
 // DoRequest sends an HTTP request to the GitLab API and returns the response body.
-// It handles request creation, authentication, execution, and error parsing.
+// It handles request creation, authentication, execution, retries, and error parsing.
 // The 'path' should be relative to the /api/v4 endpoint (e.g., "/projects/123/merge_requests/456").
 func (c *Client) DoRequest(method, path string, body interface{}) ([]byte, error) {
-	var reqBody io.Reader
+	return c.DoRequestCtx(context.Background(), method, path, body)
+}
+
+// DoRequestCtx is DoRequest under an explicit context.
+func (c *Client) DoRequestCtx(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	data, _, err := c.doRequest(ctx, method, path, body)
+	return data, err
+}
+
+// doRequest is DoRequestCtx that also returns the response headers (pagination).
+// Transient failures are retried per the client's RetryPolicy.
+func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, http.Header, error) {
+	var payload []byte
 	if body != nil {
 		jsonBytes, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBytes)
+		payload = jsonBytes
 	}
 
 	fullURL := fmt.Sprintf("%s/api/v4%s", c.baseURL, path)
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	for attempt := 0; ; attempt++ {
+		data, header, err := c.attempt(ctx, method, fullURL, payload)
+		if err == nil {
+			return data, header, nil
+		}
+		if ctx.Err() != nil || attempt >= c.retry.MaxRetries || !c.retry.shouldRetry(method, err) {
+			return nil, nil, err
+		}
+		wait := c.retry.delay(attempt, err, time.Now())
+		c.logf("[gitlab] %s %s failed: %v; retry %d/%d in %s", method, path, err, attempt+1, c.retry.MaxRetries, wait)
+		if serr := c.sleep(ctx, wait); serr != nil {
+			return nil, nil, fmt.Errorf("%w (gave up retrying: %v)", err, serr)
+		}
+	}
+}
+
+// attempt performs one HTTP round trip per authenticator, moving on to the next
+// authenticator while GitLab rejects the credentials with 401/403.
+func (c *Client) attempt(ctx context.Context, method, fullURL string, payload []byte) ([]byte, http.Header, error) {
+	if len(c.auth) == 0 {
+		return c.send(ctx, method, fullURL, payload, nil)
+	}
+	var lastErr error
+	for i, auth := range c.auth {
+		data, header, err := c.send(ctx, method, fullURL, payload, auth)
+		var gerr *GitLabError
+		rejected := errors.As(err, &gerr) && isAuthRejected(gerr.StatusCode)
+		if err == nil || !(rejected || errors.Is(err, errAuthUnavailable)) {
+			return data, header, err
+		}
+		lastErr = err
+		if i+1 < len(c.auth) {
+			c.logf("[gitlab] %s %s: %s auth failed (%v); trying %s", method, fullURL, auth.Name(), err, c.auth[i+1].Name())
+		}
+	}
+	return nil, nil, lastErr
+}
+
+// send performs a single HTTP round trip with the given credentials.
+func (c *Client) send(ctx context.Context, method, fullURL string, payload []byte, auth Authenticator) ([]byte, http.Header, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request [%s %s]: %w", method, fullURL, err)
+		return nil, nil, fmt.Errorf("failed to create request [%s %s]: %w", method, fullURL, err)
 	}
 
-	req.Header.Set("PRIVATE-TOKEN", c.token)
-	if body != nil {
+	if auth != nil {
+		if err := auth.Authenticate(req); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", errAuthUnavailable, auth.Name(), err)
+		}
+	}
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed [%s %s]: %w", method, fullURL, err)
+		return nil, nil, fmt.Errorf("HTTP request failed [%s %s]: %w", method, fullURL, err)
 	}
 	defer resp.Body.Close()
 
 	respData, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, &GitLabError{
+		return nil, nil, &GitLabError{
 			StatusCode: resp.StatusCode,
 			Message:    http.StatusText(resp.StatusCode),
 			Body:       respData,
+			Header:     resp.Header,
 		}
 	}
 
-	return respData, nil
+	return respData, resp.Header, nil
+}
+
+// logf logs through the client's logger (log.Printf by default).
+func (c *Client) logf(format string, args ...any) {
+	if c.logger != nil {
+		c.logger(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// sleep waits between retries unless ctx ends first; replaceable in tests.
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	if c.sleepFn != nil {
+		c.sleepFn(d)
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // urlEncode safely encodes a GitLab project path (e.g., "group/project" -> "group%2Fproject").
@@ -1,13 +1,22 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 )
 
 // CommitsService defines the interface for GitLab Commit operations.
 type CommitsService interface {
 	GetCommit(sha string) (Commit, error)
+	GetCommitCtx(ctx context.Context, sha string) (Commit, error)
+	Compare(from, to string) (Comparison, error)
+	CompareCtx(ctx context.Context, from, to string) (Comparison, error)
+	CompareCommits(from, to string) ([]Commit, error)
+	CompareCommitsCtx(ctx context.Context, from, to string) ([]Commit, error)
+	ListCommits(ref string) ([]Commit, error)
+	ListCommitsCtx(ctx context.Context, ref string) ([]Commit, error)
 }
 
 // commitsService is a concrete implementation of CommitsService.
@@ -27,10 +36,30 @@ type Commit struct {
 	CommittedDate  string   `json:"committed_date"`
 }
 
+// Diff is one changed file in a comparison.
+type Diff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// Comparison is the result of comparing two refs (git log/diff from..to).
+type Comparison struct {
+	Commits []Commit `json:"commits"`
+	Diffs   []Diff   `json:"diffs"`
+}
+
 // GetCommit fetches a single commit from the project.
 func (s *commitsService) GetCommit(sha string) (Commit, error) {
+	return s.GetCommitCtx(context.Background(), sha)
+}
+
+// GetCommitCtx is GetCommit under an explicit context.
+func (s *commitsService) GetCommitCtx(ctx context.Context, sha string) (Commit, error) {
 	path := fmt.Sprintf("/projects/%s/repository/commits/%s", urlEncode(s.client.projectID), sha)
-	respData, err := s.client.DoRequest("GET", path, nil)
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
 	if err != nil {
 		return Commit{}, fmt.Errorf("failed to get commit %s: %w", sha, err)
 	}
@@ -42,3 +71,59 @@ func (s *commitsService) GetCommit(sha string) (Commit, error) {
 
 	return commit, nil
 }
+
+// Compare returns the commits and changed files between two refs, as reported
+// by GitLab's compare API. Commits are oldest first.
+func (s *commitsService) Compare(from, to string) (Comparison, error) {
+	return s.CompareCtx(context.Background(), from, to)
+}
+
+// CompareCtx is Compare under an explicit context.
+func (s *commitsService) CompareCtx(ctx context.Context, from, to string) (Comparison, error) {
+	q := url.Values{}
+	q.Set("from", from)
+	q.Set("to", to)
+	path := fmt.Sprintf("/projects/%s/repository/compare?%s", urlEncode(s.client.projectID), q.Encode())
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("failed to compare %s...%s: %w", from, to, err)
+	}
+
+	var cmp Comparison
+	if err := json.Unmarshal(respData, &cmp); err != nil {
+		return Comparison{}, fmt.Errorf("failed to unmarshal compare data: %w", err)
+	}
+	return cmp, nil
+}
+
+// CompareCommits returns the commits reachable from 'to' but not from 'from'
+// (git log from..to), oldest first.
+func (s *commitsService) CompareCommits(from, to string) ([]Commit, error) {
+	return s.CompareCommitsCtx(context.Background(), from, to)
+}
+
+// CompareCommitsCtx is CompareCommits under an explicit context.
+func (s *commitsService) CompareCommitsCtx(ctx context.Context, from, to string) ([]Commit, error) {
+	cmp, err := s.CompareCtx(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return cmp.Commits, nil
+}
+
+// ListCommits lists the full history of a ref (branch, tag or SHA), newest first.
+func (s *commitsService) ListCommits(ref string) ([]Commit, error) {
+	return s.ListCommitsCtx(context.Background(), ref)
+}
+
+// ListCommitsCtx is ListCommits under an explicit context.
+func (s *commitsService) ListCommitsCtx(ctx context.Context, ref string) ([]Commit, error) {
+	q := url.Values{}
+	q.Set("ref_name", ref)
+	path := fmt.Sprintf("/projects/%s/repository/commits?%s", urlEncode(s.client.projectID), q.Encode())
+	commits, err := ListAllCtx[Commit](ctx, s.client, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits for %s: %w", ref, err)
+	}
+	return commits, nil
+}
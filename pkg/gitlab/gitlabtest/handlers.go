@@ -0,0 +1,619 @@
+package gitlabtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"syac/pkg/gitlab"
+)
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	p := "/api/v4/projects/{project}"
+
+	mux.HandleFunc("GET "+p+"/repository/tags", s.listTags)
+	mux.HandleFunc("POST "+p+"/repository/tags", s.createTag)
+
+	mux.HandleFunc("GET "+p+"/repository/commits", s.listCommits)
+	mux.HandleFunc("POST "+p+"/repository/commits", s.commitFiles)
+	mux.HandleFunc("GET "+p+"/repository/commits/{sha}", s.getCommit)
+	mux.HandleFunc("GET "+p+"/repository/commits/{sha}/merge_requests", s.commitMergeRequests)
+	mux.HandleFunc("GET "+p+"/repository/compare", s.compare)
+
+	mux.HandleFunc("GET "+p+"/merge_requests", s.listMergeRequests)
+	mux.HandleFunc("GET "+p+"/merge_requests/{iid}", s.getMergeRequest)
+	mux.HandleFunc("PUT "+p+"/merge_requests/{iid}", s.updateMergeRequest)
+	mux.HandleFunc("GET "+p+"/merge_requests/{iid}/notes", s.listNotes)
+	mux.HandleFunc("POST "+p+"/merge_requests/{iid}/notes", s.createNote)
+	mux.HandleFunc("PUT "+p+"/merge_requests/{iid}/notes/{note}", s.updateNote)
+
+	mux.HandleFunc("GET "+p+"/releases", s.listReleases)
+	mux.HandleFunc("GET "+p+"/releases/{tag}", s.getRelease)
+	mux.HandleFunc("POST "+p+"/releases", s.createRelease)
+
+	mux.HandleFunc("GET "+p+"/protected_branches", s.listProtectedBranches)
+
+	mux.HandleFunc("GET "+p+"/repository/files/{path}", s.getFile)
+	mux.HandleFunc("POST "+p+"/repository/files/{path}", s.createFile)
+	mux.HandleFunc("PUT "+p+"/repository/files/{path}", s.updateFile)
+
+	return s.middleware(mux)
+}
+
+// middleware records the request, applies injected faults, checks the token
+// and the project, then locks the state for the handler.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		if f := s.fault(r); f != nil {
+			for k, vs := range f.Header {
+				w.Header()[k] = vs
+			}
+			writeError(w, f.Status, "injected fault")
+			return
+		}
+		if s.Token != "" && !hasToken(r, s.Token) {
+			writeError(w, http.StatusUnauthorized, "401 Unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if !strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func hasToken(r *http.Request, token string) bool {
+	return r.Header.Get("PRIVATE-TOKEN") == token ||
+		r.Header.Get("JOB-TOKEN") == token ||
+		r.Header.Get("Authorization") == "Bearer "+token
+}
+
+// project rejects requests for other projects with 404.
+func (s *Server) project(w http.ResponseWriter, r *http.Request) bool {
+	if r.PathValue("project") != s.Project {
+		writeError(w, http.StatusNotFound, "404 Project Not Found")
+		return false
+	}
+	return true
+}
+
+// ---------- Tags ----------
+
+func (s *Server) listTags(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	tags := slices.Clone(s.tags)
+	switch q.Get("order_by") {
+	case "", "updated":
+		slices.Reverse(tags) // newest first
+	case "name":
+		slices.SortStableFunc(tags, func(a, b gitlab.Tag) int { return strings.Compare(b.Name, a.Name) })
+	case "version":
+		slices.SortStableFunc(tags, func(a, b gitlab.Tag) int { return versionCompare(b.Name, a.Name) })
+	default:
+		writeError(w, http.StatusBadRequest, "order_by does not have a valid value")
+		return
+	}
+	if q.Get("sort") == "asc" {
+		slices.Reverse(tags)
+	}
+	if search := q.Get("search"); search != "" {
+		tags = slices.DeleteFunc(tags, func(t gitlab.Tag) bool { return !matchSearch(t.Name, search) })
+	}
+	writePage(w, r, tags)
+}
+
+func (s *Server) createTag(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	var body struct {
+		TagName string `json:"tag_name"`
+		Ref     string `json:"ref"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.TagName == "" || body.Ref == "" {
+		writeError(w, http.StatusBadRequest, "tag_name and ref are required")
+		return
+	}
+	tag, err := s.addTagLocked(body.TagName, body.Ref)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, tag)
+}
+
+// ---------- Commits ----------
+
+func (s *Server) listCommits(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	end := s.resolve(r.URL.Query().Get("ref_name"))
+	if end < 0 {
+		writeError(w, http.StatusNotFound, "404 Commit Not Found")
+		return
+	}
+	var out []gitlab.Commit
+	for i := end; i >= 0; i-- {
+		out = append(out, s.commits[i].Commit)
+	}
+	writePage(w, r, out)
+}
+
+func (s *Server) getCommit(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	i := s.resolve(r.PathValue("sha"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "404 Commit Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.commits[i].Commit)
+}
+
+func (s *Server) commitMergeRequests(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	var out []gitlab.MergeRequest
+	for _, iid := range s.mrCommits[r.PathValue("sha")] {
+		if mr := s.findMR(iid); mr != nil {
+			out = append(out, mr.MergeRequest)
+		}
+	}
+	writePage(w, r, out)
+}
+
+func (s *Server) compare(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	from, to := s.resolve(r.URL.Query().Get("from")), s.resolve(r.URL.Query().Get("to"))
+	if from < 0 || to < 0 {
+		writeError(w, http.StatusNotFound, "404 Ref Not Found")
+		return
+	}
+	out := gitlab.Comparison{Commits: []gitlab.Commit{}, Diffs: []gitlab.Diff{}}
+	seen := map[string]bool{}
+	for i := from + 1; i <= to; i++ {
+		out.Commits = append(out.Commits, s.commits[i].Commit)
+		for _, p := range s.commits[i].paths {
+			if !seen[p] {
+				seen[p] = true
+				out.Diffs = append(out.Diffs, gitlab.Diff{OldPath: p, NewPath: p})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) commitFiles(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	var body struct {
+		Branch        string              `json:"branch"`
+		CommitMessage string              `json:"commit_message"`
+		Actions       []gitlab.FileAction `json:"actions"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Branch == "" || body.CommitMessage == "" || len(body.Actions) == 0 {
+		writeError(w, http.StatusBadRequest, "branch, commit_message and actions are required")
+		return
+	}
+	var paths []string
+	for _, a := range body.Actions {
+		_, exists := s.files[body.Branch][a.FilePath]
+		switch a.Action {
+		case "create":
+			if exists {
+				writeError(w, http.StatusBadRequest, "A file with this name already exists")
+				return
+			}
+		case "update", "delete":
+			if !exists {
+				writeError(w, http.StatusBadRequest, "A file with this name doesn't exist")
+				return
+			}
+			if a.LastCommitID != "" && a.LastCommitID != s.lastCommitLocked(a.FilePath) {
+				writeError(w, http.StatusBadRequest, "You are attempting to update a file that has changed since you started editing it.")
+				return
+			}
+		default:
+			writeError(w, http.StatusBadRequest, "unsupported action "+a.Action)
+			return
+		}
+		paths = append(paths, a.FilePath)
+	}
+	for _, a := range body.Actions {
+		if a.Action == "delete" {
+			delete(s.files[body.Branch], a.FilePath)
+		} else {
+			s.setFileLocked(body.Branch, a.FilePath, a.Content)
+		}
+	}
+	c := s.addCommitLocked(gitlab.Commit{Message: body.CommitMessage}, paths)
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// ---------- Merge requests ----------
+
+func (s *Server) listMergeRequests(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	state := r.URL.Query().Get("state")
+	var out []gitlab.MergeRequest
+	for i := len(s.mrs) - 1; i >= 0; i-- { // most recently added first
+		if state == "" || state == "all" || s.mrs[i].State == state {
+			out = append(out, s.mrs[i].MergeRequest)
+		}
+	}
+	writePage(w, r, out)
+}
+
+func (s *Server) getMergeRequest(w http.ResponseWriter, r *http.Request) {
+	mr := s.mergeRequest(w, r)
+	if mr == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, mr)
+}
+
+func (s *Server) updateMergeRequest(w http.ResponseWriter, r *http.Request) {
+	mr := s.mergeRequest(w, r)
+	if mr == nil {
+		return
+	}
+	var body struct {
+		Description  *string `json:"description"`
+		Labels       *string `json:"labels"`        // comma-separated; replaces all labels
+		AddLabels    string  `json:"add_labels"`    // comma-separated
+		RemoveLabels string  `json:"remove_labels"` // comma-separated
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Description != nil {
+		mr.Description = *body.Description
+	}
+	if body.Labels != nil {
+		mr.Labels = nil
+		body.AddLabels = *body.Labels + "," + body.AddLabels
+	}
+	for _, l := range splitLabels(body.RemoveLabels) {
+		mr.Labels = slices.DeleteFunc(mr.Labels, func(have string) bool { return strings.EqualFold(have, l) })
+	}
+	for _, l := range splitLabels(body.AddLabels) {
+		if !slices.ContainsFunc(mr.Labels, func(have string) bool { return strings.EqualFold(have, l) }) {
+			mr.Labels = append(mr.Labels, l)
+		}
+	}
+	writeJSON(w, http.StatusOK, mr)
+}
+
+func (s *Server) listNotes(w http.ResponseWriter, r *http.Request) {
+	mr := s.mergeRequest(w, r)
+	if mr == nil {
+		return
+	}
+	writePage(w, r, s.notes[mr.IID])
+}
+
+func (s *Server) createNote(w http.ResponseWriter, r *http.Request) {
+	mr := s.mergeRequest(w, r)
+	if mr == nil {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	id := s.addNoteLocked(mr.IID, body.Body)
+	writeJSON(w, http.StatusCreated, gitlab.Note{ID: id, Body: body.Body})
+}
+
+func (s *Server) updateNote(w http.ResponseWriter, r *http.Request) {
+	mr := s.mergeRequest(w, r)
+	if mr == nil {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	id, _ := strconv.Atoi(r.PathValue("note"))
+	notes := s.notes[mr.IID]
+	i := slices.IndexFunc(notes, func(n gitlab.Note) bool { return n.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "404 Note Not Found")
+		return
+	}
+	notes[i].Body = body.Body
+	writeJSON(w, http.StatusOK, notes[i])
+}
+
+// mergeRequest resolves {project} and {iid}, answering 404 when unknown.
+func (s *Server) mergeRequest(w http.ResponseWriter, r *http.Request) *mergeRequest {
+	if !s.project(w, r) {
+		return nil
+	}
+	iid, _ := strconv.Atoi(r.PathValue("iid"))
+	mr := s.findMR(iid)
+	if mr == nil {
+		writeError(w, http.StatusNotFound, "404 Not found")
+	}
+	return mr
+}
+
+// ---------- Releases ----------
+
+func (s *Server) listReleases(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	releases := slices.Clone(s.releases)
+	slices.Reverse(releases) // newest first
+	writePage(w, r, releases)
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	tag := r.PathValue("tag")
+	i := slices.IndexFunc(s.releases, func(rel gitlab.Release) bool { return rel.TagName == tag })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.releases[i])
+}
+
+func (s *Server) createRelease(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	var body gitlab.ReleasePayload
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if slices.ContainsFunc(s.releases, func(rel gitlab.Release) bool { return rel.TagName == body.TagName }) {
+		writeError(w, http.StatusConflict, "Release already exists")
+		return
+	}
+	if s.findTag(body.TagName) < 0 {
+		// GitLab creates the tag from ref when it doesn't exist yet.
+		if _, err := s.addTagLocked(body.TagName, body.Ref); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	}
+	rel := gitlab.Release{
+		TagName:     body.TagName,
+		Name:        body.Name,
+		Description: body.Description,
+		Ref:         body.Ref,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	s.releases = append(s.releases, rel)
+	writeJSON(w, http.StatusCreated, rel)
+}
+
+// ---------- Branches / files ----------
+
+func (s *Server) listProtectedBranches(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	writePage(w, r, s.protected)
+}
+
+// getFile serves files stored on a branch; ref must be the branch name.
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	if !s.project(w, r) {
+		return
+	}
+	ref, path := r.URL.Query().Get("ref"), r.PathValue("path")
+	content, ok := s.files[ref][path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 File Not Found")
+		return
+	}
+	head := ""
+	if i := s.resolve(ref); i >= 0 {
+		head = s.commits[i].ID
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"file_path":      path,
+		"ref":            ref,
+		"encoding":       "base64",
+		"content":        base64.StdEncoding.EncodeToString([]byte(content)),
+		"commit_id":      head,
+		"last_commit_id": s.lastCommitLocked(path),
+	})
+}
+
+func (s *Server) createFile(w http.ResponseWriter, r *http.Request) {
+	s.writeFile(w, r, false)
+}
+
+func (s *Server) updateFile(w http.ResponseWriter, r *http.Request) {
+	s.writeFile(w, r, true)
+}
+
+func (s *Server) writeFile(w http.ResponseWriter, r *http.Request, update bool) {
+	if !s.project(w, r) {
+		return
+	}
+	var body struct {
+		Branch        string `json:"branch"`
+		CommitMessage string `json:"commit_message"`
+		Content       string `json:"content"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	path := r.PathValue("path")
+	_, exists := s.files[body.Branch][path]
+	switch {
+	case update && !exists:
+		writeError(w, http.StatusBadRequest, "A file with this name doesn't exist")
+		return
+	case !update && exists:
+		writeError(w, http.StatusBadRequest, "A file with this name already exists")
+		return
+	}
+	s.setFileLocked(body.Branch, path, body.Content)
+	s.addCommitLocked(gitlab.Commit{Message: body.CommitMessage}, []string{path})
+
+	status := http.StatusCreated
+	if update {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]string{"file_path": path, "branch": body.Branch})
+}
+
+// ---------- Helpers ----------
+
+// writePage writes one page of items with GitLab's offset pagination headers.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	perPage, err := strconv.Atoi(q.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+
+	h := w.Header()
+	h.Set("X-Page", strconv.Itoa(page))
+	h.Set("X-Per-Page", strconv.Itoa(perPage))
+	h.Set("X-Total", strconv.Itoa(len(items)))
+	if end < len(items) {
+		h.Set("X-Next-Page", strconv.Itoa(page+1))
+	} else {
+		h.Set("X-Next-Page", "")
+	}
+	pageItems := items[start:end]
+	if pageItems == nil {
+		pageItems = []T{}
+	}
+	writeJSON(w, http.StatusOK, pageItems)
+}
+
+// matchSearch implements GitLab's tag search: "^prefix", "suffix$" or substring.
+func matchSearch(name, search string) bool {
+	switch {
+	case strings.HasPrefix(search, "^"):
+		return strings.HasPrefix(name, search[1:])
+	case strings.HasSuffix(search, "$"):
+		return strings.HasSuffix(name, strings.TrimSuffix(search, "$"))
+	}
+	return strings.Contains(name, search)
+}
+
+// versionCompare orders tag names like git's version sort: runs of digits
+// compare numerically, everything else byte-wise, so 1.10.0 > 1.9.0. As in
+// git without versionsort.suffix, 1.0.0-rc.1 sorts after 1.0.0.
+func versionCompare(a, b string) int {
+	for a != "" && b != "" {
+		ra, rb := leadingRun(a), leadingRun(b)
+		da, db := isDigit(ra[0]), isDigit(rb[0])
+		var c int
+		switch {
+		case da && db:
+			na, _ := strconv.Atoi(ra)
+			nb, _ := strconv.Atoi(rb)
+			c = na - nb
+		default:
+			c = strings.Compare(ra, rb)
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = a[len(ra):], b[len(rb):]
+	}
+	return len(a) - len(b)
+}
+
+func leadingRun(s string) string {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
+
+// splitLabels splits GitLab's comma-separated label parameters.
+func splitLabels(raw string) []string {
+	var out []string
+	for _, l := range strings.Split(raw, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
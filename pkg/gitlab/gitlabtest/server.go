@@ -0,0 +1,352 @@
+// Package gitlabtest provides an in-memory fake of the GitLab REST API
+// endpoints syac uses (tags, notes, MR descriptions and labels, commits, releases,
+// protected branches, repository files), for tests.
+//
+// The fake keeps a single linear history: commits are appended in order and
+// every ref (branch name or SHA) resolves to a point in that history.
+//
+//	srv := gitlabtest.NewServer(t)
+//	srv.AddCommit(gitlab.Commit{ID: "a1"}, "main.go")
+//	srv.AddTag("1.2.3", "a1")
+//	latest, _ := srv.Client().Tags.GetLatestTag()
+package gitlabtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"syac/pkg/gitlab"
+)
+
+// DefaultPerPage is the page size used when a request doesn't set per_page,
+// matching GitLab's default.
+const DefaultPerPage = 20
+
+// Server is a fake GitLab instance backed by in-memory state. It is safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Project is the only project the fake serves (ID or "group/project").
+	Project string
+	// Token, when non-empty, must be sent (as PRIVATE-TOKEN, JOB-TOKEN or
+	// Bearer) or the request is rejected with 401.
+	Token string
+
+	t  testing.TB
+	mu sync.Mutex
+
+	commits   []commit // oldest first
+	tags      []gitlab.Tag
+	mrs       []*mergeRequest
+	mrCommits map[string][]int // commit SHA -> MR IIDs containing it
+	notes     map[int][]gitlab.Note
+	releases  []gitlab.Release
+	protected []gitlab.ProtectedBranch
+	files     map[string]map[string]string // branch -> path -> content
+	faults    []*Fault
+	requests  []string
+	nextID    int
+}
+
+type commit struct {
+	gitlab.Commit
+	paths []string // files changed by the commit
+}
+
+type mergeRequest struct {
+	gitlab.MergeRequest
+	Description string `json:"description"`
+}
+
+// Fault makes matching requests fail.
+type Fault struct {
+	Method string      // e.g. "POST"; "" matches any method
+	Path   string      // substring of the unescaped request path, e.g. "/repository/tags"
+	Status int         // HTTP status to answer with
+	Header http.Header // extra response headers, e.g. Retry-After
+	Times  int         // how many requests to fail; 0 fails every match
+}
+
+// NewServer starts a fake serving project "1" with token "test-token".
+// It is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		Project:   "1",
+		Token:     "test-token",
+		t:         t,
+		mrCommits: map[string][]int{},
+		notes:     map[int][]gitlab.Note{},
+		files:     map[string]map[string]string{},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a gitlab.Client pointed at the fake; opts are applied last,
+// e.g. gitlab.WithRetryPolicy(gitlab.RetryPolicy{}) to fail fast on faults.
+func (s *Server) Client(opts ...gitlab.Option) *gitlab.Client {
+	s.t.Helper()
+	c, err := gitlab.New(s.URL, append([]gitlab.Option{
+		gitlab.WithToken(s.Token),
+		gitlab.WithProject(s.Project),
+		gitlab.WithHTTPClient(s.Server.Client()),
+	}, opts...)...)
+	if err != nil {
+		s.t.Fatalf("gitlabtest: %v", err)
+	}
+	return c
+}
+
+// ---------- Seeding ----------
+
+// AddCommit appends c to the history; paths are the files it changes (used
+// by the compare endpoint). ShortID, Title and ParentIDs are filled in when empty.
+func (s *Server) AddCommit(c gitlab.Commit, paths ...string) gitlab.Commit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addCommitLocked(c, paths)
+}
+
+func (s *Server) addCommitLocked(c gitlab.Commit, paths []string) gitlab.Commit {
+	if c.ID == "" {
+		s.nextID++
+		c.ID = fmt.Sprintf("%040x", s.nextID)
+	}
+	if c.ShortID == "" {
+		c.ShortID = c.ID[:min(8, len(c.ID))]
+	}
+	if c.Title == "" {
+		c.Title, _, _ = strings.Cut(c.Message, "\n")
+	}
+	if c.ParentIDs == nil && len(s.commits) > 0 {
+		c.ParentIDs = []string{s.commits[len(s.commits)-1].ID}
+	}
+	s.commits = append(s.commits, commit{Commit: c, paths: paths})
+	return c
+}
+
+// AddTag tags the commit ref resolves to (a SHA or branch name; the head of
+// history if ref is empty).
+func (s *Server) AddTag(name, ref string) gitlab.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tag, err := s.addTagLocked(name, ref)
+	if err != nil {
+		s.t.Fatalf("gitlabtest: AddTag(%q, %q): %v", name, ref, err)
+	}
+	return tag
+}
+
+func (s *Server) addTagLocked(name, ref string) (gitlab.Tag, error) {
+	if s.findTag(name) >= 0 {
+		return gitlab.Tag{}, fmt.Errorf("Tag %s already exists", name)
+	}
+	tag := gitlab.Tag{Name: name, Target: ref}
+	if i := s.resolve(ref); i >= 0 {
+		c := s.commits[i].Commit
+		tag.Target, tag.Commit = c.ID, &c
+	} else if ref != "" || len(s.commits) > 0 {
+		return gitlab.Tag{}, fmt.Errorf("invalid reference name: %s", ref)
+	}
+	s.tags = append(s.tags, tag)
+	return tag, nil
+}
+
+// AddMergeRequest registers an MR with its description. Commits listed in
+// commitSHAs (plus its merge/squash commit) report the MR as theirs.
+func (s *Server) AddMergeRequest(mr gitlab.MergeRequest, description string, commitSHAs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mr.State == "" {
+		mr.State = "opened"
+	}
+	s.mrs = append(s.mrs, &mergeRequest{MergeRequest: mr, Description: description})
+	for _, sha := range append(commitSHAs, mr.MergeCommitSHA, mr.SquashCommitSHA) {
+		if sha != "" && !slices.Contains(s.mrCommits[sha], mr.IID) {
+			s.mrCommits[sha] = append(s.mrCommits[sha], mr.IID)
+		}
+	}
+}
+
+// AddNote adds a note to MR iid and returns its ID.
+func (s *Server) AddNote(iid int, body string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addNoteLocked(iid, body)
+}
+
+func (s *Server) addNoteLocked(iid int, body string) int {
+	s.nextID++
+	s.notes[iid] = append(s.notes[iid], gitlab.Note{ID: s.nextID, Body: body})
+	return s.nextID
+}
+
+// AddRelease registers an existing release.
+func (s *Server) AddRelease(r gitlab.Release) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releases = append(s.releases, r)
+}
+
+// AddProtectedBranch marks a branch as protected.
+func (s *Server) AddProtectedBranch(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protected = append(s.protected, gitlab.ProtectedBranch{Name: name})
+}
+
+// SetFile stores a repository file on branch.
+func (s *Server) SetFile(branch, path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setFileLocked(branch, path, content)
+}
+
+func (s *Server) setFileLocked(branch, path, content string) {
+	if s.files[branch] == nil {
+		s.files[branch] = map[string]string{}
+	}
+	s.files[branch][path] = content
+}
+
+// Inject registers a fault; faults are checked in registration order.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ---------- Inspection ----------
+
+// Tags returns the tags in creation order.
+func (s *Server) Tags() []gitlab.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.tags)
+}
+
+// Commits returns the history, oldest first.
+func (s *Server) Commits() []gitlab.Commit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]gitlab.Commit, len(s.commits))
+	for i, c := range s.commits {
+		out[i] = c.Commit
+	}
+	return out
+}
+
+// Notes returns the notes of MR iid.
+func (s *Server) Notes(iid int) []gitlab.Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.notes[iid])
+}
+
+// Description returns the description of MR iid.
+func (s *Server) Description(iid int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mr := s.findMR(iid); mr != nil {
+		return mr.Description
+	}
+	return ""
+}
+
+// Labels returns the labels of MR iid.
+func (s *Server) Labels(iid int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mr := s.findMR(iid); mr != nil {
+		return slices.Clone(mr.Labels)
+	}
+	return nil
+}
+
+// Releases returns the releases in creation order.
+func (s *Server) Releases() []gitlab.Release {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.releases)
+}
+
+// File returns a repository file on branch.
+func (s *Server) File(branch, path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.files[branch][path]
+	return content, ok
+}
+
+// Requests returns every request served so far as "METHOD /path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// ---------- Lookups (callers hold mu) ----------
+
+// resolve returns the history index of a SHA, tag or branch name (any branch
+// is the head of history), or -1.
+func (s *Server) resolve(ref string) int {
+	if ref == "" {
+		return len(s.commits) - 1
+	}
+	for i, c := range s.commits {
+		if c.ID == ref || c.ShortID == ref {
+			return i
+		}
+	}
+	if i := s.findTag(ref); i >= 0 {
+		return s.resolveSHA(s.tags[i].CommitID())
+	}
+	if _, ok := s.files[ref]; ok || s.isProtected(ref) || ref == "main" || ref == "master" {
+		return len(s.commits) - 1
+	}
+	return -1
+}
+
+func (s *Server) resolveSHA(sha string) int {
+	for i, c := range s.commits {
+		if c.ID == sha {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastCommitLocked returns the newest commit that changed path, or "".
+func (s *Server) lastCommitLocked(path string) string {
+	for i := len(s.commits) - 1; i >= 0; i-- {
+		if slices.Contains(s.commits[i].paths, path) {
+			return s.commits[i].ID
+		}
+	}
+	return ""
+}
+
+func (s *Server) findTag(name string) int {
+	return slices.IndexFunc(s.tags, func(t gitlab.Tag) bool { return t.Name == name })
+}
+
+func (s *Server) findMR(iid int) *mergeRequest {
+	for _, mr := range s.mrs {
+		if mr.IID == iid {
+			return mr
+		}
+	}
+	return nil
+}
+
+func (s *Server) isProtected(branch string) bool {
+	return slices.ContainsFunc(s.protected, func(b gitlab.ProtectedBranch) bool { return b.Name == branch })
+}
@@ -1,6 +1,8 @@
 package gitlab
 
 import (
+	"context"
+
 	"syac/internal/version"
 )
 
@@ -8,22 +10,46 @@ import (
 
 type MergeRequestsService interface {
 	GetMergeRequestDescription(mrID string) (string, error)
+	GetMergeRequestDescriptionCtx(ctx context.Context, mrID string) (string, error)
 	UpdateMergeRequestDescription(mrID string, newDescription string) error
+	UpdateMergeRequestDescriptionCtx(ctx context.Context, mrID string, newDescription string) error
 	InsertReleaseTypeInDescription(mrID string) error
+	InsertReleaseTypeInDescriptionCtx(ctx context.Context, mrID string) error
 
 	CreateMergeRequestComment(mrID string) error
-	UpsertMergeRequestComment(mrID string) error
+	CreateMergeRequestCommentCtx(ctx context.Context, mrID string) error
+	UpsertMergeRequestComment(mrID string, forecasts ...*ReleaseForecast) error
+	UpsertMergeRequestCommentCtx(ctx context.Context, mrID string, forecasts ...*ReleaseForecast) error
+	UpsertBumpConflictNote(mrID string, sel BumpSelection) error
+	UpsertBumpConflictNoteCtx(ctx context.Context, mrID string, sel BumpSelection) error
 	
 
 	GetVersionBump(mrID string) (version.VersionType, error)
+	GetVersionBumpCtx(ctx context.Context, mrID string) (version.VersionType, error)
+	ResolveVersionBump(mrID string) (BumpSelection, error)
+	ResolveVersionBumpCtx(ctx context.Context, mrID string) (BumpSelection, error)
+	SetBumpLabel(mrID string, bump version.VersionType) error
+	SetBumpLabelCtx(ctx context.Context, mrID string, bump version.VersionType) error
 	GetMergeRequestForCommit(sha string) (MergeRequest, error)
+	GetMergeRequestForCommitCtx(ctx context.Context, sha string) (MergeRequest, error)
 	GetLatestMergeRequest() (MergeRequest, error)
+	GetLatestMergeRequestCtx(ctx context.Context) (MergeRequest, error)
+
+	// Notes use the client's project like every other method; use
+	// Client.Project for another project.
+	ListNotes(mrID string) ([]Note, error)
+	ListNotesCtx(ctx context.Context, mrID string) ([]Note, error)
+	UpdateNote(mrID string, noteID int, body string) error
+	UpdateNoteCtx(ctx context.Context, mrID string, noteID int, body string) error
+	CreateNote(mrID string, body string) error
+	CreateNoteCtx(ctx context.Context, mrID string, body string) error
 
-	ListNotes(projectID, mrID string) ([]Note, error)
-	UpdateNote(projectID, mrID string, noteID int, body string) error
-	CreateNote(projectID, mrID string, body string) error
+	// WithBumpLabels returns a view that reads (or mirrors) the release type
+	// with the given labels instead of DefaultBumpLabels.
+	WithBumpLabels(labels BumpLabels) MergeRequestsService
 }
 
 type mrsService struct {
 	client *Client
+	labels BumpLabels // zero fields fall back to DefaultBumpLabels
 }
@@ -0,0 +1,115 @@
+package gitlab
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"syac/internal/assets"
+	"syac/internal/version"
+)
+
+// releaseTypeBoxRe matches one release-type checkbox line of a SYAC block
+// rendered before the block had an end marker.
+var releaseTypeBoxRe = regexp.MustCompile(`- \[[ x]\] \*\*(Patch|Minor|Major)\*\*`)
+
+// releaseTypeOption is one checkbox of the SYAC block.
+type releaseTypeOption struct {
+	Type        version.VersionType
+	Checked     bool
+	Placeholder string   // shown without a forecast, e.g. "*.x.*"
+	Next        []string // forecast tag per component
+}
+
+// latestRelease is one "Latest release" entry of the SYAC block.
+type latestRelease struct {
+	Component string
+	Tag       string // "" before the first release
+}
+
+// renderReleaseTypeBlock renders the embedded SYAC block template with every
+// selected type ticked and, for every non-nil forecast (one per monorepo
+// component), the concrete next version of every choice. Nothing is ticked
+// when selected is empty: ResolveVersionBump falls back to Patch anyway, and a
+// pre-ticked default would conflict with a release-type label added later.
+func renderReleaseTypeBlock(selected []version.VersionType, forecasts []*ReleaseForecast) (string, error) {
+	contentBytes, err := assets.MrCommentContent.ReadFile("mr_comment.md")
+	if err != nil {
+		return "", fmt.Errorf("read embedded: %w", err)
+	}
+	tmpl, err := template.New("mr_comment.md").Parse(string(contentBytes))
+	if err != nil {
+		return "", fmt.Errorf("parse mr_comment.md: %w", err)
+	}
+
+	data := struct {
+		Options []releaseTypeOption
+		Latest  []latestRelease
+		Images  []string
+	}{
+		Options: []releaseTypeOption{
+			{Type: version.Patch, Placeholder: "*.*.x"},
+			{Type: version.Minor, Placeholder: "*.x.*"},
+			{Type: version.Major, Placeholder: "x.*.*"},
+		},
+	}
+	for _, f := range forecasts {
+		if f == nil {
+			continue
+		}
+		for i := range data.Options {
+			data.Options[i].Next = append(data.Options[i].Next, f.next(data.Options[i].Type))
+		}
+		data.Latest = append(data.Latest, latestRelease{Component: f.Component, Tag: f.LatestTag})
+		data.Images = append(data.Images, f.Images...)
+	}
+	for i := range data.Options {
+		data.Options[i].Checked = slices.Contains(selected, data.Options[i].Type)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render mr_comment.md: %w", err)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// findReleaseTypeBlock locates the SYAC block in text, from its marker to
+// its end marker. Blocks rendered without an end marker span the prompt and
+// checkbox lines directly after the marker. end is exclusive.
+func findReleaseTypeBlock(text string) (start, end int, ok bool) {
+	start = strings.Index(text, syacMarker)
+	if start < 0 {
+		return 0, 0, false
+	}
+	end = start + len(syacMarker)
+	if i := strings.Index(text[end:], syacEndMarker); i >= 0 {
+		return start, end + i + len(syacEndMarker), true
+	}
+	for end < len(text) && text[end] == '\n' {
+		line, _, _ := strings.Cut(text[end+1:], "\n")
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "[SYAC]") && !releaseTypeBoxRe.MatchString(trimmed) {
+			break
+		}
+		end += 1 + len(line)
+	}
+	return start, end, true
+}
+
+// rerenderReleaseTypeBlock returns text with its SYAC block re-rendered from
+// the template, keeping every box the author ticked so a conflicting
+// selection stays visible (and reported) until the author resolves it.
+func rerenderReleaseTypeBlock(text string, forecasts []*ReleaseForecast) (string, error) {
+	start, end, ok := findReleaseTypeBlock(text)
+	if !ok {
+		return text, nil
+	}
+	block, err := renderReleaseTypeBlock(ParseVersionBumps(text[start:end]), forecasts)
+	if err != nil {
+		return "", err
+	}
+	return text[:start] + block + text[end:], nil
+}
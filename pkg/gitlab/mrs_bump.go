@@ -1,52 +1,164 @@
 package gitlab
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"syac/internal/version"
 )
 
+// Sources of a BumpSelection.
+const (
+	SelectionLabel       = "label"
+	SelectionNote        = "note"
+	SelectionDescription = "description"
+	SelectionDefault     = "default"
+)
+
+// BumpSelection is the release type an MR selects, where it came from, and
+// any disagreement found while resolving it.
+type BumpSelection struct {
+	Bump      version.VersionType
+	Source    string   // SelectionLabel, SelectionNote, SelectionDescription or SelectionDefault
+	Conflicts []string // empty when the selection is unambiguous
+}
+
+// Ambiguous reports whether the author's selection conflicts with itself.
+func (s BumpSelection) Ambiguous() bool {
+	return len(s.Conflicts) > 0
+}
+
+var checkboxRe = regexp.MustCompile(`- \[x\] \*\*(Patch|Minor|Major)\*\*`)
+
+// ParseVersionBump returns the first ticked release type in text.
 func ParseVersionBump(text string) (version.VersionType, bool) {
-	checkboxRe := regexp.MustCompile(`- \[x\] \*\*(Patch|Minor|Major)\*\*`)
+	if bumps := ParseVersionBumps(text); len(bumps) > 0 {
+		return bumps[0], true
+	}
+	return "", false
+}
+
+// ParseVersionBumps returns every distinct ticked release type in text, in order.
+func ParseVersionBumps(text string) []version.VersionType {
+	var out []version.VersionType
 	for _, line := range strings.Split(text, "\n") {
-		if m := checkboxRe.FindStringSubmatch(line); len(m) > 1 {
-			return version.VersionType(m[1]), true
+		if m := checkboxRe.FindStringSubmatch(line); len(m) > 1 && !slices.Contains(out, version.VersionType(m[1])) {
+			out = append(out, version.VersionType(m[1]))
 		}
 	}
-	return "", false
+	return out
 }
 
+// GetVersionBump returns the MR's selected release type (see ResolveVersionBump).
 func (s *mrsService) GetVersionBump(mrID string) (version.VersionType, error) {
+	return s.GetVersionBumpCtx(context.Background(), mrID)
+}
+
+// GetVersionBumpCtx is GetVersionBump under an explicit context.
+func (s *mrsService) GetVersionBumpCtx(ctx context.Context, mrID string) (version.VersionType, error) {
 	if s == nil || s.client == nil {
 		return "", fmt.Errorf("GetVersionBump: nil client")
 	}
+	sel, err := s.ResolveVersionBumpCtx(ctx, mrID)
+	if err != nil {
+		return "", fmt.Errorf("GetVersionBump: %w", err)
+	}
+	return sel.Bump, nil
+}
+
+// ResolveVersionBump reads the MR's release type. Precedence:
+//
+//  1. a release-type label (see BumpLabels), unless labels mirror the checkbox
+//  2. the newest SYAC note with a ticked box
+//  3. the MR description block
+//  4. a mirrored label (checkboxes all blank)
+//  5. Patch
+//
+// Several ticked boxes or labels, or sources that disagree, are reported as
+// Conflicts; the preferred source still wins.
+func (s *mrsService) ResolveVersionBump(mrID string) (BumpSelection, error) {
+	return s.ResolveVersionBumpCtx(context.Background(), mrID)
+}
 
-	// 1) Prefer the SYAC note. If there are multiple, prefer the most recent.
-	if notes, err := s.ListNotes(s.client.projectID, mrID); err == nil && len(notes) > 0 {
+// ResolveVersionBumpCtx is ResolveVersionBump under an explicit context.
+func (s *mrsService) ResolveVersionBumpCtx(ctx context.Context, mrID string) (BumpSelection, error) {
+	if s == nil || s.client == nil {
+		return BumpSelection{}, fmt.Errorf("ResolveVersionBump: nil client")
+	}
+	labels := s.bumpLabels()
+
+	// The SYAC note. If there are multiple, prefer the most recent.
+	var fromNote []version.VersionType
+	if notes, err := s.ListNotesCtx(ctx, mrID); err == nil {
 		// iterate from newest to oldest (GitLab often returns ascending; play it safe)
-		for i := len(notes) - 1; i >= 0; i-- {
-			n := notes[i]
-			if strings.Contains(n.Body, syacMarker) {
-				if bump, ok := ParseVersionBump(n.Body); ok {
-					return bump, nil
-				}
-				// Found our note but no box checked; keep looking for an older one
-				// If none match, we’ll fall back to description.
+		for i := len(notes) - 1; i >= 0 && len(fromNote) == 0; i-- {
+			if strings.Contains(notes[i].Body, syacMarker) {
+				fromNote = ParseVersionBumps(notes[i].Body)
 			}
 		}
 	}
 
-	// 2) Fallback: parse MR description
-	desc, err := s.GetMergeRequestDescription(mrID)
+	// The MR description and labels.
+	mr, err := s.getMergeRequest(ctx, mrID)
 	if err != nil {
-		return "", fmt.Errorf("GetVersionBump: %w", err)
+		return BumpSelection{}, fmt.Errorf("ResolveVersionBump: %w", err)
+	}
+	fromDesc := ParseVersionBumps(mr.Description)
+	fromLabels := labels.bumps(mr.Labels)
+
+	// The checkbox selection: note first, then description.
+	box, boxSource := fromDesc, SelectionDescription
+	if len(fromNote) > 0 {
+		box, boxSource = fromNote, SelectionNote
 	}
-	if bump, ok := ParseVersionBump(desc); ok {
-		return bump, nil
+
+	sel := BumpSelection{Bump: version.Patch, Source: SelectionDefault}
+	switch {
+	case len(fromLabels) > 0 && !labels.Mirror:
+		sel.Bump, sel.Source = fromLabels[0], SelectionLabel
+	case len(box) > 0:
+		sel.Bump, sel.Source = box[0], boxSource
+	case len(fromLabels) > 0:
+		sel.Bump, sel.Source = fromLabels[0], SelectionLabel
 	}
 
-	// 3) Nothing selected anywhere → default
-	return version.Patch, nil
+	if len(fromNote) > 1 {
+		sel.Conflicts = append(sel.Conflicts, fmt.Sprintf("the SYAC note ticks %s", joinBumps(fromNote)))
+	}
+	if len(fromDesc) > 1 {
+		sel.Conflicts = append(sel.Conflicts, fmt.Sprintf("the MR description ticks %s", joinBumps(fromDesc)))
+	}
+	if len(fromNote) == 1 && len(fromDesc) == 1 && fromNote[0] != fromDesc[0] {
+		sel.Conflicts = append(sel.Conflicts, fmt.Sprintf("the SYAC note selects %s but the MR description selects %s", fromNote[0], fromDesc[0]))
+	}
+	// Mirrored labels are synced from the checkbox, so they can't conflict with it.
+	if !labels.Mirror {
+		if len(fromLabels) > 1 {
+			sel.Conflicts = append(sel.Conflicts, fmt.Sprintf("the MR has %s labels", joinBumps(fromLabels)))
+		}
+		if len(fromLabels) == 1 && len(box) == 1 && fromLabels[0] != box[0] {
+			sel.Conflicts = append(sel.Conflicts, fmt.Sprintf("the %s label selects %s but the %s selects %s",
+				labels.label(fromLabels[0]), fromLabels[0], boxSourceName(boxSource), box[0]))
+		}
+	}
+	return sel, nil
+}
+
+// boxSourceName describes a checkbox source for conflict messages.
+func boxSourceName(source string) string {
+	if source == SelectionNote {
+		return "SYAC note"
+	}
+	return "MR description"
+}
+
+func joinBumps(bumps []version.VersionType) string {
+	names := make([]string, len(bumps))
+	for i, b := range bumps {
+		names[i] = string(b)
+	}
+	return strings.Join(names, " and ")
 }
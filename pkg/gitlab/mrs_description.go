@@ -1,78 +1,100 @@
 package gitlab
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
 
-	"syac/internal/assets"
+	"syac/internal/version"
 )
 
 func (s *mrsService) GetMergeRequestDescription(mrID string) (string, error) {
+	return s.GetMergeRequestDescriptionCtx(context.Background(), mrID)
+}
+
+// GetMergeRequestDescriptionCtx is GetMergeRequestDescription under an explicit context.
+func (s *mrsService) GetMergeRequestDescriptionCtx(ctx context.Context, mrID string) (string, error) {
 	if s == nil || s.client == nil {
 		return "", fmt.Errorf("GetMergeRequestDescription: nil client")
 	}
-	path := fmt.Sprintf("/projects/%s/merge_requests/%s", urlEncode(s.client.projectID), mrID)
-	respData, err := s.client.DoRequest("GET", path, nil)
+	mr, err := s.getMergeRequest(ctx, mrID)
 	if err != nil {
 		return "", fmt.Errorf("GetMergeRequestDescription: %w", err)
 	}
-	var mr struct {
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal(respData, &mr); err != nil {
-		return "", fmt.Errorf("GetMergeRequestDescription: unmarshal: %w", err)
-	}
 	return mr.Description, nil
 }
 
 func (s *mrsService) UpdateMergeRequestDescription(mrID string, newDescription string) error {
+	return s.UpdateMergeRequestDescriptionCtx(context.Background(), mrID, newDescription)
+}
+
+// UpdateMergeRequestDescriptionCtx is UpdateMergeRequestDescription under an explicit context.
+func (s *mrsService) UpdateMergeRequestDescriptionCtx(ctx context.Context, mrID string, newDescription string) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("UpdateMergeRequestDescription: nil client")
 	}
 	path := fmt.Sprintf("/projects/%s/merge_requests/%s", urlEncode(s.client.projectID), mrID)
 	payload := map[string]string{"description": newDescription}
-	if _, err := s.client.DoRequest("PUT", path, payload); err != nil {
+	if _, err := s.client.DoRequestCtx(ctx, "PUT", path, payload); err != nil {
 		return fmt.Errorf("UpdateMergeRequestDescription: %w", err)
 	}
 	return nil
 }
 
 // InsertReleaseTypeInDescription ensures the SYAC release-type block is present
-// in the MR description. If already present, it leaves the description untouched.
+// in the MR description. An existing block is re-rendered in place keeping the
+// author's selection; the description is only updated when it changes.
 func (s *mrsService) InsertReleaseTypeInDescription(mrID string) error {
+	return s.InsertReleaseTypeInDescriptionCtx(context.Background(), mrID)
+}
+
+// InsertReleaseTypeInDescriptionCtx is InsertReleaseTypeInDescription under an explicit context.
+func (s *mrsService) InsertReleaseTypeInDescriptionCtx(ctx context.Context, mrID string) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("InsertReleaseTypeInDescription: nil client")
 	}
 
-	// Load the embedded SYAC block (same block used for MR comment).
-	contentBytes, err := assets.MrCommentContent.ReadFile("mr_comment.md")
-	if err != nil {
-		return fmt.Errorf("InsertReleaseTypeInDescription: read embedded: %w", err)
-	}
-	block := string(contentBytes)
-
 	// Get current description.
-	desc, err := s.GetMergeRequestDescription(mrID)
+	mr, err := s.getMergeRequest(ctx, mrID)
 	if err != nil {
 		return fmt.Errorf("InsertReleaseTypeInDescription: get description: %w", err)
 	}
+	desc := mr.Description
 
-	// If marker already present, nothing to do.
+	var newDesc string
 	if strings.Contains(desc, syacMarker) {
-		return nil
+		// Refresh the block, keeping whatever the author ticked.
+		newDesc, err = rerenderReleaseTypeBlock(desc, nil)
+		if err != nil {
+			return fmt.Errorf("InsertReleaseTypeInDescription: %w", err)
+		}
+	} else {
+		// Load the SYAC block (same block used for MR comment), pre-ticked
+		// from a release-type label so the two don't disagree.
+		var selected []version.VersionType
+		if labels := s.bumpLabels(); !labels.Mirror {
+			if bumps := labels.bumps(mr.Labels); len(bumps) > 0 {
+				selected = bumps[:1]
+			}
+		}
+		block, err := renderReleaseTypeBlock(selected, nil)
+		if err != nil {
+			return fmt.Errorf("InsertReleaseTypeInDescription: %w", err)
+		}
+		// Append the block with spacing.
+		if strings.TrimSpace(desc) == "" {
+			newDesc = block + "\n"
+		} else {
+			newDesc = strings.TrimRight(desc, "\r\n") + "\n\n" + block + "\n"
+		}
 	}
 
-	// Append the block with spacing.
-	var newDesc string
-	if strings.TrimSpace(desc) == "" {
-		newDesc = block + "\n"
-	} else {
-		newDesc = strings.TrimRight(desc, "\r\n") + "\n\n" + block + "\n"
+	if newDesc == desc {
+		return nil
 	}
 
 	// Push update back to GitLab.
-	if err := s.UpdateMergeRequestDescription(mrID, newDesc); err != nil {
+	if err := s.UpdateMergeRequestDescriptionCtx(ctx, mrID, newDesc); err != nil {
 		return fmt.Errorf("InsertReleaseTypeInDescription: update: %w", err)
 	}
 
@@ -0,0 +1,138 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"syac/internal/version"
+)
+
+// BumpLabels names the MR labels that select a release type.
+type BumpLabels struct {
+	Patch, Minor, Major string
+
+	// Mirror makes the labels follow the checkbox selection (see SetBumpLabel)
+	// instead of overriding it.
+	Mirror bool
+}
+
+// DefaultBumpLabels are GitLab scoped labels, so an MR carries at most one.
+var DefaultBumpLabels = BumpLabels{
+	Patch: "release::patch",
+	Minor: "release::minor",
+	Major: "release::major",
+}
+
+// label returns the label selecting bump.
+func (l BumpLabels) label(bump version.VersionType) string {
+	switch bump {
+	case version.Patch:
+		return l.Patch
+	case version.Minor:
+		return l.Minor
+	case version.Major:
+		return l.Major
+	}
+	return ""
+}
+
+// bumps returns the release types selected by labels, in label order.
+func (l BumpLabels) bumps(labels []string) []version.VersionType {
+	var out []version.VersionType
+	for _, name := range labels {
+		for _, b := range []version.VersionType{version.Patch, version.Minor, version.Major} {
+			if strings.EqualFold(name, l.label(b)) && !slices.Contains(out, b) {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+func (s *mrsService) WithBumpLabels(labels BumpLabels) MergeRequestsService {
+	return &mrsService{client: s.client, labels: labels}
+}
+
+// bumpLabels returns the view's labels, with DefaultBumpLabels for unset names.
+func (s *mrsService) bumpLabels() BumpLabels {
+	l := s.labels
+	if l.Patch == "" {
+		l.Patch = DefaultBumpLabels.Patch
+	}
+	if l.Minor == "" {
+		l.Minor = DefaultBumpLabels.Minor
+	}
+	if l.Major == "" {
+		l.Major = DefaultBumpLabels.Major
+	}
+	return l
+}
+
+// mergeRequestDetail is a single MR as returned by GET /merge_requests/:iid.
+type mergeRequestDetail struct {
+	MergeRequest
+	Description string `json:"description"`
+}
+
+func (s *mrsService) getMergeRequest(ctx context.Context, mrID string) (mergeRequestDetail, error) {
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s", urlEncode(s.client.projectID), mrID)
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
+	if err != nil {
+		return mergeRequestDetail{}, err
+	}
+	var mr mergeRequestDetail
+	if err := json.Unmarshal(respData, &mr); err != nil {
+		return mergeRequestDetail{}, fmt.Errorf("unmarshal: %w", err)
+	}
+	return mr, nil
+}
+
+// SetBumpLabel makes bump's label the MR's only release-type label, so the
+// selection shows up in MR lists. An MR that already carries exactly that
+// label is left alone.
+func (s *mrsService) SetBumpLabel(mrID string, bump version.VersionType) error {
+	return s.SetBumpLabelCtx(context.Background(), mrID, bump)
+}
+
+// SetBumpLabelCtx is SetBumpLabel under an explicit context.
+func (s *mrsService) SetBumpLabelCtx(ctx context.Context, mrID string, bump version.VersionType) error {
+	if s == nil || s.client == nil {
+		return fmt.Errorf("SetBumpLabel: nil client")
+	}
+	labels := s.bumpLabels()
+	want := labels.label(bump)
+	if want == "" {
+		return fmt.Errorf("SetBumpLabel: unknown release type %q", bump)
+	}
+	mr, err := s.getMergeRequest(ctx, mrID)
+	if err != nil {
+		return fmt.Errorf("SetBumpLabel: %w", err)
+	}
+
+	var remove []string
+	has := false
+	for _, name := range mr.Labels {
+		switch {
+		case strings.EqualFold(name, want):
+			has = true
+		case len(labels.bumps([]string{name})) > 0:
+			remove = append(remove, name)
+		}
+	}
+	if has && len(remove) == 0 {
+		return nil
+	}
+
+	payload := map[string]string{"add_labels": want}
+	if len(remove) > 0 {
+		payload["remove_labels"] = strings.Join(remove, ",")
+	}
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s", urlEncode(s.client.projectID), mrID)
+	if _, err := s.client.DoRequestCtx(ctx, "PUT", path, payload); err != nil {
+		return fmt.Errorf("SetBumpLabel: %w", err)
+	}
+	return nil
+}
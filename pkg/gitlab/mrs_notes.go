@@ -1,89 +1,179 @@
 package gitlab
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
 
-	"syac/internal/assets"
+	"syac/internal/version"
 )
 
 // ---------- Notes (comments) ----------
 
 func (s *mrsService) CreateMergeRequestComment(mrID string) error {
+	return s.CreateMergeRequestCommentCtx(context.Background(), mrID)
+}
+
+// CreateMergeRequestCommentCtx is CreateMergeRequestComment under an explicit context.
+func (s *mrsService) CreateMergeRequestCommentCtx(ctx context.Context, mrID string) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("CreateMergeRequestComment: nil client")
 	}
-	contentBytes, err := assets.MrCommentContent.ReadFile("mr_comment.md")
+	body, err := renderReleaseTypeBlock(nil, nil)
 	if err != nil {
-		return fmt.Errorf("CreateMergeRequestComment: read embedded: %w", err)
+		return fmt.Errorf("CreateMergeRequestComment: %w", err)
 	}
-	return s.CreateNote(s.client.projectID, mrID, string(contentBytes))
+	return s.CreateNoteCtx(ctx, mrID, body)
 }
 
-func (s *mrsService) UpsertMergeRequestComment(mrID string) error {
+// UpsertMergeRequestComment creates the SYAC note, or re-renders the existing
+// one keeping every release type the author ticked. Each non-nil forecast adds
+// the latest release, the next version per choice and the pushed images; a
+// monorepo passes one forecast per component so they share the one note.
+// An up-to-date note is left alone so reruns don't bump its "edited" time or
+// notify anyone.
+func (s *mrsService) UpsertMergeRequestComment(mrID string, forecasts ...*ReleaseForecast) error {
+	return s.UpsertMergeRequestCommentCtx(context.Background(), mrID, forecasts...)
+}
+
+// UpsertMergeRequestCommentCtx is UpsertMergeRequestComment under an explicit context.
+func (s *mrsService) UpsertMergeRequestCommentCtx(ctx context.Context, mrID string, forecasts ...*ReleaseForecast) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("UpsertMergeRequestComment: nil client")
 	}
-	contentBytes, err := assets.MrCommentContent.ReadFile("mr_comment.md")
+
+	notes, err := s.ListNotesCtx(ctx, mrID)
 	if err != nil {
-		return fmt.Errorf("UpsertMergeRequestComment: read embedded: %w", err)
+		return fmt.Errorf("UpsertMergeRequestComment: list notes: %w", err)
 	}
-	body := string(contentBytes)
 
-	notes, err := s.ListNotes(s.client.projectID, mrID)
+	var existing *Note
+	for i := range notes {
+		if strings.Contains(notes[i].Body, syacMarker) {
+			existing = &notes[i]
+			break
+		}
+	}
+
+	if existing == nil {
+		// Start from the current selection (label, description) so the
+		// sources don't disagree; the Patch default stays unticked.
+		var selected []version.VersionType
+		if sel, err := s.ResolveVersionBumpCtx(ctx, mrID); err == nil && sel.Source != SelectionDefault {
+			selected = []version.VersionType{sel.Bump}
+		}
+		body, err := renderReleaseTypeBlock(selected, forecasts)
+		if err != nil {
+			return fmt.Errorf("UpsertMergeRequestComment: %w", err)
+		}
+		return s.CreateNoteCtx(ctx, mrID, body)
+	}
+
+	// Keep every ticked box: collapsing to one would erase a conflict.
+	body, err := renderReleaseTypeBlock(ParseVersionBumps(existing.Body), forecasts)
 	if err != nil {
-		return fmt.Errorf("UpsertMergeRequestComment: list notes: %w", err)
+		return fmt.Errorf("UpsertMergeRequestComment: %w", err)
+	}
+	if strings.TrimSpace(body) == strings.TrimSpace(existing.Body) {
+		return nil
+	}
+	return s.UpdateNoteCtx(ctx, mrID, existing.ID, body)
+}
+
+// UpsertBumpConflictNote posts, or refreshes, a SYAC warning note listing the
+// conflicts of sel. Once sel is unambiguous an existing warning is marked
+// resolved; an MR that never conflicted gets no note.
+func (s *mrsService) UpsertBumpConflictNote(mrID string, sel BumpSelection) error {
+	return s.UpsertBumpConflictNoteCtx(context.Background(), mrID, sel)
+}
+
+// UpsertBumpConflictNoteCtx is UpsertBumpConflictNote under an explicit context.
+func (s *mrsService) UpsertBumpConflictNoteCtx(ctx context.Context, mrID string, sel BumpSelection) error {
+	if s == nil || s.client == nil {
+		return fmt.Errorf("UpsertBumpConflictNote: nil client")
 	}
 
-	var existingID int
-	for _, n := range notes {
-		if strings.Contains(n.Body, syacMarker) {
-			existingID = n.ID
+	notes, err := s.ListNotesCtx(ctx, mrID)
+	if err != nil {
+		return fmt.Errorf("UpsertBumpConflictNote: list notes: %w", err)
+	}
+	var existing *Note
+	for i := range notes {
+		if strings.Contains(notes[i].Body, conflictMarker) {
+			existing = &notes[i]
 			break
 		}
 	}
 
-	if existingID == 0 {
-		return s.CreateNote(s.client.projectID, mrID, body)
+	var b strings.Builder
+	b.WriteString(conflictMarker + "\n")
+	if sel.Ambiguous() {
+		b.WriteString("⚠️ **[SYAC] Conflicting release-type selection**\n\n")
+		for _, c := range sel.Conflicts {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+		fmt.Fprintf(&b, "\nsyac uses **%s** (from the %s) until exactly one box is ticked.", sel.Bump, sel.Source)
+	} else {
+		fmt.Fprintf(&b, "✅ **[SYAC] Release-type selection resolved**: **%s** (from the %s).", sel.Bump, sel.Source)
 	}
-	return s.UpdateNote(s.client.projectID, mrID, existingID, body)
+	body := b.String()
+
+	switch {
+	case existing == nil && !sel.Ambiguous():
+		return nil
+	case existing == nil:
+		return s.CreateNoteCtx(ctx, mrID, body)
+	case strings.TrimSpace(existing.Body) == body:
+		return nil
+	}
+	return s.UpdateNoteCtx(ctx, mrID, existing.ID, body)
+}
+
+func (s *mrsService) ListNotes(mrID string) ([]Note, error) {
+	return s.ListNotesCtx(context.Background(), mrID)
 }
 
-func (s *mrsService) ListNotes(projectID, mrID string) ([]Note, error) {
+// ListNotesCtx is ListNotes under an explicit context.
+func (s *mrsService) ListNotesCtx(ctx context.Context, mrID string) ([]Note, error) {
 	if s == nil || s.client == nil {
 		return nil, fmt.Errorf("ListNotes: nil client")
 	}
-	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", urlEncode(projectID), mrID)
-	data, err := s.client.DoRequest("GET", path, nil)
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", urlEncode(s.client.projectID), mrID)
+	notes, err := ListAllCtx[Note](ctx, s.client, path)
 	if err != nil {
 		return nil, fmt.Errorf("ListNotes: %w", err)
 	}
-	var notes []Note
-	if err := json.Unmarshal(data, &notes); err != nil {
-		return nil, fmt.Errorf("ListNotes: unmarshal: %w", err)
-	}
 	return notes, nil
 }
 
-func (s *mrsService) CreateNote(projectID, mrID string, body string) error {
+func (s *mrsService) CreateNote(mrID string, body string) error {
+	return s.CreateNoteCtx(context.Background(), mrID, body)
+}
+
+// CreateNoteCtx is CreateNote under an explicit context.
+func (s *mrsService) CreateNoteCtx(ctx context.Context, mrID string, body string) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("CreateNote: nil client")
 	}
-	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", urlEncode(projectID), mrID)
-	_, err := s.client.DoRequest("POST", path, map[string]string{"body": body})
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", urlEncode(s.client.projectID), mrID)
+	_, err := s.client.DoRequestCtx(ctx, "POST", path, map[string]string{"body": body})
 	if err != nil {
 		return fmt.Errorf("CreateNote: %w", err)
 	}
 	return nil
 }
 
-func (s *mrsService) UpdateNote(projectID, mrID string, noteID int, body string) error {
+func (s *mrsService) UpdateNote(mrID string, noteID int, body string) error {
+	return s.UpdateNoteCtx(context.Background(), mrID, noteID, body)
+}
+
+// UpdateNoteCtx is UpdateNote under an explicit context.
+func (s *mrsService) UpdateNoteCtx(ctx context.Context, mrID string, noteID int, body string) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("UpdateNote: nil client")
 	}
-	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", urlEncode(projectID), mrID, noteID)
-	_, err := s.client.DoRequest("PUT", path, map[string]string{"body": body})
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", urlEncode(s.client.projectID), mrID, noteID)
+	_, err := s.client.DoRequestCtx(ctx, "PUT", path, map[string]string{"body": body})
 	if err != nil {
 		return fmt.Errorf("UpdateNote: %w", err)
 	}
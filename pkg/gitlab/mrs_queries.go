@@ -1,33 +1,106 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 )
 
+// GetMergeRequestForCommit returns the MR that brought sha into its branch.
+// On the default branch sha is usually a merge or squash commit, which GitLab
+// does not always associate with its MR, so the lookup falls back to:
+//  1. the MR containing the merge commit's second parent (the MR head)
+//  2. recently merged MRs whose merge_commit_sha / squash_commit_sha is sha
+//
+// Returns an error wrapping ErrNoMergeRequests if none is found.
 func (s *mrsService) GetMergeRequestForCommit(sha string) (MergeRequest, error) {
+	return s.GetMergeRequestForCommitCtx(context.Background(), sha)
+}
+
+// GetMergeRequestForCommitCtx is GetMergeRequestForCommit under an explicit context.
+func (s *mrsService) GetMergeRequestForCommitCtx(ctx context.Context, sha string) (MergeRequest, error) {
 	if s == nil || s.client == nil {
 		return MergeRequest{}, fmt.Errorf("GetMergeRequestForCommit: nil client")
 	}
-	path := fmt.Sprintf("/projects/%s/repository/commits/%s/merge_requests", urlEncode(s.client.projectID), sha)
-	respData, err := s.client.DoRequest("GET", path, nil)
+	mr, ok, err := s.mergeRequestContaining(ctx, sha)
 	if err != nil {
 		return MergeRequest{}, fmt.Errorf("GetMergeRequestForCommit: %w", err)
 	}
+	if ok {
+		return mr, nil
+	}
+
+	// Merge commit: the second parent is the MR's head commit.
+	if commit, err := s.client.Commits.GetCommitCtx(ctx, sha); err == nil && len(commit.ParentIDs) > 1 {
+		if mr, ok, err := s.mergeRequestContaining(ctx, commit.ParentIDs[1]); err == nil && ok {
+			return mr, nil
+		}
+	}
+
+	// Squash (or fast-forward merge) commit: match it on the merged MRs.
+	if mr, ok, err := s.mergedMergeRequestBySHA(ctx, sha); err == nil && ok {
+		return mr, nil
+	}
+	return MergeRequest{}, fmt.Errorf("GetMergeRequestForCommit: %w for commit %s", ErrNoMergeRequests, sha)
+}
+
+// mergeRequestContaining asks GitLab which MRs contain sha and picks the best:
+// the MR that merged/squashed into sha, then any merged MR. Open or closed MRs
+// that merely contain sha did not bring it in and are not returned.
+func (s *mrsService) mergeRequestContaining(ctx context.Context, sha string) (MergeRequest, bool, error) {
+	path := fmt.Sprintf("/projects/%s/repository/commits/%s/merge_requests", urlEncode(s.client.projectID), sha)
+	mrs, err := ListAllCtx[MergeRequest](ctx, s.client, path)
+	if err != nil {
+		return MergeRequest{}, false, err
+	}
+	for _, mr := range mrs {
+		if mr.landedAs(sha) {
+			return mr, true, nil
+		}
+	}
+	for _, mr := range mrs {
+		if mr.State == "merged" {
+			return mr, true, nil
+		}
+	}
+	return MergeRequest{}, false, nil
+}
+
+// mergedMergeRequestBySHA scans the most recently merged MRs for one that
+// landed as sha. Deliberately a single page: sha is a fresh default-branch commit.
+func (s *mrsService) mergedMergeRequestBySHA(ctx context.Context, sha string) (MergeRequest, bool, error) {
+	q := url.Values{}
+	q.Set("state", "merged")
+	q.Set("order_by", "updated_at")
+	q.Set("sort", "desc")
+	q.Set("per_page", "50")
+
+	path := fmt.Sprintf("/projects/%s/merge_requests?%s", urlEncode(s.client.projectID), q.Encode())
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
+	if err != nil {
+		return MergeRequest{}, false, err
+	}
 	var mrs []MergeRequest
 	if err := json.Unmarshal(respData, &mrs); err != nil {
-		return MergeRequest{}, fmt.Errorf("GetMergeRequestForCommit: unmarshal: %w", err)
+		return MergeRequest{}, false, fmt.Errorf("unmarshal: %w", err)
 	}
-	if len(mrs) == 0 {
-		return MergeRequest{}, fmt.Errorf("GetMergeRequestForCommit: none for commit %s", sha)
+	for _, mr := range mrs {
+		if mr.landedAs(sha) {
+			return mr, true, nil
+		}
 	}
-	return mrs[0], nil
+	return MergeRequest{}, false, nil
 }
 
 // GetLatestMergeRequest: most-recent by updated_at (more useful for automation).
 // Returns ErrNoMergeRequests if none found so callers can branch cleanly.
 func (s *mrsService) GetLatestMergeRequest() (MergeRequest, error) {
+	return s.GetLatestMergeRequestCtx(context.Background())
+}
+
+// GetLatestMergeRequestCtx is GetLatestMergeRequest under an explicit context.
+func (s *mrsService) GetLatestMergeRequestCtx(ctx context.Context) (MergeRequest, error) {
 	if s == nil || s.client == nil {
 		return MergeRequest{}, fmt.Errorf("GetLatestMergeRequest: nil client")
 	}
@@ -38,7 +111,7 @@ func (s *mrsService) GetLatestMergeRequest() (MergeRequest, error) {
 	q.Set("sort", "desc")
 
 	path := fmt.Sprintf("/projects/%s/merge_requests?%s", urlEncode(s.client.projectID), q.Encode())
-	respData, err := s.client.DoRequest("GET", path, nil)
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
 	if err != nil {
 		return MergeRequest{}, fmt.Errorf("GetLatestMergeRequest: fetch failed: %w", err)
 	}
@@ -0,0 +1,358 @@
+package gitlab_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestUpsertMergeRequestCommentAndBump(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 7, Title: "feat: thing"}, "Adds a thing.")
+	srv.AddNote(7, "LGTM")
+	mrs := srv.Client().MergeRequests
+
+	for i := 0; i < 2; i++ { // second run updates instead of duplicating
+		if err := mrs.UpsertMergeRequestComment("7", nil); err != nil {
+			t.Fatalf("UpsertMergeRequestComment: %v", err)
+		}
+	}
+	notes := srv.Notes(7)
+	if len(notes) != 2 {
+		t.Fatalf("notes = %d, want 2 (LGTM + one SYAC note)", len(notes))
+	}
+
+	// The author ticks Minor in the SYAC note.
+	body := strings.Replace(notes[1].Body, "- [ ] **Minor**", "- [x] **Minor**", 1)
+	if err := mrs.UpdateNote("7", notes[1].ID, body); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	bump, err := mrs.GetVersionBump("7")
+	if err != nil || bump != version.Minor {
+		t.Fatalf("GetVersionBump = %s, %v; want Minor", bump, err)
+	}
+}
+
+func TestInsertReleaseTypeInDescriptionIsIdempotent(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 3}, "Fixes #12")
+	mrs := srv.Client().MergeRequests
+
+	for i := 0; i < 2; i++ {
+		if err := mrs.InsertReleaseTypeInDescription("3"); err != nil {
+			t.Fatalf("InsertReleaseTypeInDescription: %v", err)
+		}
+	}
+	desc := srv.Description(3)
+	if !strings.HasPrefix(desc, "Fixes #12\n\n") || strings.Count(desc, "syac:release-type") != 1 {
+		t.Fatalf("description = %q, want original text + one SYAC block", desc)
+	}
+}
+
+func TestGetMergeRequestForCommit(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	base := srv.AddCommit(gitlab.Commit{Message: "init"})
+	head := srv.AddCommit(gitlab.Commit{Message: "feat: work"})
+	merge := srv.AddCommit(gitlab.Commit{Message: "Merge branch 'work'", ParentIDs: []string{base.ID, head.ID}})
+	squash := srv.AddCommit(gitlab.Commit{Message: "fix: squashed"})
+
+	// GitLab only links the MR head commit; merge/squash commits need fallbacks.
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 1, State: "merged"}, "", head.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 2, State: "merged", SquashCommitSHA: squash.ID}, "")
+	mrs := srv.Client().MergeRequests
+
+	for sha, want := range map[string]int{head.ID: 1, merge.ID: 1, squash.ID: 2} {
+		mr, err := mrs.GetMergeRequestForCommit(sha)
+		if err != nil || mr.IID != want {
+			t.Errorf("GetMergeRequestForCommit(%s) = !%d, %v; want !%d", sha[:8], mr.IID, err, want)
+		}
+	}
+	if _, err := mrs.GetMergeRequestForCommit(base.ID); err == nil {
+		t.Error("commit without MR should fail")
+	}
+
+	// A commit only contained in open or closed MRs was not brought in by them.
+	pending := srv.AddCommit(gitlab.Commit{Message: "feat: pending"})
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 3, State: "opened"}, "", pending.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 4, State: "closed"}, "", pending.ID)
+	if mr, err := mrs.GetMergeRequestForCommit(pending.ID); !errors.Is(err, gitlab.ErrNoMergeRequests) {
+		t.Errorf("commit in open/closed MRs = !%d, %v; want ErrNoMergeRequests", mr.IID, err)
+	}
+}
+
+// tick returns the SYAC block with only bump checked.
+func tick(body string, bump version.VersionType) string {
+	for _, b := range []version.VersionType{version.Patch, version.Minor, version.Major} {
+		from, to := "- [x] **"+string(b)+"**", "- [ ] **"+string(b)+"**"
+		if b == bump {
+			from, to = to, from
+		}
+		body = strings.Replace(body, from, to, 1)
+	}
+	return body
+}
+
+func countUpdates(srv *gitlabtest.Server) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r, "PUT ") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestUpsertMergeRequestCommentKeepsSelection(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 7}, "")
+	mrs := srv.Client().MergeRequests
+	if err := mrs.UpsertMergeRequestComment("7", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The author picks Major; an outdated prompt line must still be refreshed.
+	note := srv.Notes(7)[0]
+	edited := strings.Replace(tick(note.Body, version.Major), "[SYAC]", "[SYAC] (old wording)", 1)
+	if err := mrs.UpdateNote("7", note.ID, edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := mrs.UpsertMergeRequestComment("7", nil); err != nil {
+		t.Fatal(err)
+	}
+	body := srv.Notes(7)[0].Body
+	if body != tick(note.Body, version.Major) {
+		t.Fatalf("note after rerun = %q, want template with Major ticked", body)
+	}
+
+	puts := countUpdates(srv)
+	if err := mrs.UpsertMergeRequestComment("7", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := countUpdates(srv); got != puts {
+		t.Fatalf("unchanged note was updated again (%d PUTs, want %d)", got, puts)
+	}
+	if bump, _ := mrs.GetVersionBump("7"); bump != version.Major {
+		t.Fatalf("GetVersionBump = %s, want Major", bump)
+	}
+}
+
+func TestInsertReleaseTypeInDescriptionKeepsSelection(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 3}, "Fixes #12")
+	mrs := srv.Client().MergeRequests
+	if err := mrs.InsertReleaseTypeInDescription("3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The author ticks Minor and keeps writing below the block.
+	desc := tick(srv.Description(3), version.Minor) + "\nMore notes.\n"
+	if err := mrs.UpdateMergeRequestDescription("3", desc); err != nil {
+		t.Fatal(err)
+	}
+	puts := countUpdates(srv)
+	if err := mrs.InsertReleaseTypeInDescription("3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Description(3); got != desc {
+		t.Fatalf("description = %q, want %q untouched", got, desc)
+	}
+	if got := countUpdates(srv); got != puts {
+		t.Fatalf("unchanged description was updated (%d PUTs, want %d)", got, puts)
+	}
+}
+
+func TestResolveVersionBumpReportsConflicts(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	block, minor := "<!-- syac:release-type -->\n", "- [x] **Minor**\n"
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 4}, block+"- [x] **Major**\n")
+	noteID := srv.AddNote(4, block+minor+"- [x] **Major**\n")
+	mrs := srv.Client().MergeRequests
+
+	sel, err := mrs.ResolveVersionBump("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Bump != version.Minor || sel.Source != gitlab.SelectionNote || len(sel.Conflicts) != 1 {
+		t.Fatalf("selection = %+v, want Minor from note with one conflict", sel)
+	}
+
+	// Fixing the note still leaves it disagreeing with the description.
+	if err := mrs.UpdateNote("4", noteID, block+minor); err != nil {
+		t.Fatal(err)
+	}
+	sel, _ = mrs.ResolveVersionBump("4")
+	if !sel.Ambiguous() || !strings.Contains(sel.Conflicts[0], "selects Minor but the MR description selects Major") {
+		t.Fatalf("conflicts = %q, want note/description disagreement", sel.Conflicts)
+	}
+	if err := mrs.UpsertBumpConflictNote("4", sel); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mrs.UpdateMergeRequestDescription("4", block+minor); err != nil {
+		t.Fatal(err)
+	}
+	sel, _ = mrs.ResolveVersionBump("4")
+	if sel.Ambiguous() || sel.Bump != version.Minor {
+		t.Fatalf("selection = %+v, want unambiguous Minor", sel)
+	}
+	if err := mrs.UpsertBumpConflictNote("4", sel); err != nil {
+		t.Fatal(err)
+	}
+	notes := srv.Notes(4)
+	if len(notes) != 2 || !strings.Contains(notes[1].Body, "resolved") {
+		t.Fatalf("notes = %+v, want the SYAC note plus one resolved warning", notes)
+	}
+}
+
+func TestRerenderingKeepsConflictingTicks(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 8}, "")
+	mrs := srv.Client().MergeRequests
+	both := func(body string) string {
+		return strings.Replace(tick(body, version.Minor), "- [ ] **Major**", "- [x] **Major**", 1)
+	}
+	resolve := func(step string, conflicts int) {
+		t.Helper()
+		sel, err := mrs.ResolveVersionBump("8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sel.Conflicts) != conflicts {
+			t.Fatalf("%s: conflicts = %q, want %d", step, sel.Conflicts, conflicts)
+		}
+	}
+
+	// The author ticks Minor and Major in the description; a rerun keeps both.
+	if err := mrs.InsertReleaseTypeInDescription("8"); err != nil {
+		t.Fatal(err)
+	}
+	desc := both(srv.Description(8))
+	if err := mrs.UpdateMergeRequestDescription("8", desc); err != nil {
+		t.Fatal(err)
+	}
+	if err := mrs.InsertReleaseTypeInDescription("8"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Description(8); got != desc {
+		t.Fatalf("description = %q, want both ticks kept", got)
+	}
+	resolve("description", 1)
+
+	// The same in the note: the refresh must not settle the conflict for them.
+	if err := mrs.UpsertMergeRequestComment("8"); err != nil {
+		t.Fatal(err)
+	}
+	note := srv.Notes(8)[0]
+	if err := mrs.UpdateNote("8", note.ID, both(note.Body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mrs.UpsertMergeRequestComment("8"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Notes(8)[0].Body; got != both(note.Body) {
+		t.Fatalf("note = %q, want both ticks kept", got)
+	}
+	resolve("note", 2)
+}
+
+func TestUpsertBumpConflictNoteSkipsUnambiguousMRs(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 2}, "")
+	sel := gitlab.BumpSelection{Bump: version.Patch, Source: gitlab.SelectionDefault}
+	if err := srv.Client().MergeRequests.UpsertBumpConflictNote("2", sel); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Notes(2)); n != 0 {
+		t.Fatalf("notes = %d, want none", n)
+	}
+}
+
+func TestLabelAddedAfterBlocksDoesNotConflict(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 9}, "")
+	mrs := srv.Client().MergeRequests
+	upsert := func() {
+		t.Helper()
+		if err := mrs.InsertReleaseTypeInDescription("9"); err != nil {
+			t.Fatal(err)
+		}
+		if err := mrs.UpsertMergeRequestComment("9"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing selected yet: the blocks tick nothing and Patch is the default.
+	upsert()
+	if strings.Contains(srv.Description(9), "[x]") || strings.Contains(srv.Notes(9)[0].Body, "[x]") {
+		t.Fatalf("blocks tick a default:\n%s\n%s", srv.Description(9), srv.Notes(9)[0].Body)
+	}
+	if sel, _ := mrs.ResolveVersionBump("9"); sel.Bump != version.Patch || sel.Source != gitlab.SelectionDefault {
+		t.Fatalf("selection = %+v, want the Patch default", sel)
+	}
+
+	// A triager labels the MR afterwards; the untouched blocks don't disagree.
+	if err := mrs.SetBumpLabel("9", version.Minor); err != nil {
+		t.Fatal(err)
+	}
+	upsert()
+	sel, err := mrs.ResolveVersionBump("9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Bump != version.Minor || sel.Source != gitlab.SelectionLabel || sel.Ambiguous() {
+		t.Fatalf("selection = %+v, want unambiguous Minor from the label", sel)
+	}
+}
+
+func TestResolveVersionBumpFromLabels(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	block := "<!-- syac:release-type -->\n- [x] **Patch**\n"
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 6, Labels: []string{"backend", "release::minor"}}, block)
+	mrs := srv.Client().MergeRequests
+
+	// A label beats the checkbox; the disagreement is reported.
+	sel, err := mrs.ResolveVersionBump("6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Bump != version.Minor || sel.Source != gitlab.SelectionLabel || len(sel.Conflicts) != 1 {
+		t.Fatalf("selection = %+v, want Minor from label with one conflict", sel)
+	}
+
+	// Custom label names.
+	custom := mrs.WithBumpLabels(gitlab.BumpLabels{Major: "breaking"})
+	if err := custom.SetBumpLabel("6", version.Major); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Labels(6); strings.Join(got, ",") != "backend,breaking" {
+		t.Fatalf("labels = %q, want backend,breaking", got)
+	}
+	if sel, _ := custom.ResolveVersionBump("6"); sel.Bump != version.Major || sel.Source != gitlab.SelectionLabel {
+		t.Fatalf("selection = %+v, want Major from the breaking label", sel)
+	}
+
+	// Mirrored labels follow the checkbox instead.
+	mirror := mrs.WithBumpLabels(gitlab.BumpLabels{Major: "breaking", Mirror: true})
+	sel, _ = mirror.ResolveVersionBump("6")
+	if sel.Bump != version.Patch || sel.Source != gitlab.SelectionDescription || sel.Ambiguous() {
+		t.Fatalf("mirror selection = %+v, want unambiguous Patch from description", sel)
+	}
+	if err := mirror.SetBumpLabel("6", sel.Bump); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Labels(6); strings.Join(got, ",") != "backend,release::patch" {
+		t.Fatalf("labels = %q, want backend,release::patch", got)
+	}
+
+	puts := countUpdates(srv)
+	if err := mirror.SetBumpLabel("6", version.Patch); err != nil {
+		t.Fatal(err)
+	}
+	if got := countUpdates(srv); got != puts {
+		t.Fatal("SetBumpLabel updated an MR that already had the label")
+	}
+}
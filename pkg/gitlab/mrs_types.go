@@ -1,9 +1,15 @@
 package gitlab
 
-import "errors"
+import (
+	"errors"
+
+	"syac/internal/version"
+)
 
 var (
 	syacMarker         = "<!-- syac:release-type -->"
+	syacEndMarker      = "<!-- syac:end -->"
+	conflictMarker     = "<!-- syac:bump-conflict -->"
 	ErrNoMergeRequests = errors.New("no merge requests")
 )
 
@@ -11,3 +17,26 @@ type Note struct {
 	ID   int    `json:"id"`
 	Body string `json:"body"`
 }
+
+// ReleaseForecast is what the SYAC block shows next to the release-type
+// choices: the latest release and the tag each choice would produce. A
+// monorepo MR gets one forecast per affected component in the same block.
+type ReleaseForecast struct {
+	Component           string   // monorepo component; "" for a single application
+	LatestTag           string   // "" before the first release
+	Patch, Minor, Major string   // next tag per bump
+	Images              []string // image refs the MR pipeline pushed
+}
+
+// next returns the forecast tag for bump.
+func (f *ReleaseForecast) next(bump version.VersionType) string {
+	switch bump {
+	case version.Patch:
+		return f.Patch
+	case version.Minor:
+		return f.Minor
+	case version.Major:
+		return f.Major
+	}
+	return ""
+}
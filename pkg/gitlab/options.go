@@ -0,0 +1,98 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Client built with New.
+type Option func(*Client)
+
+const defaultTimeout = 10 * time.Second
+
+// DefaultUserAgent is sent unless overridden with WithUserAgent.
+const DefaultUserAgent = "syac"
+
+// New creates a GitLab client for the instance at baseURL (e.g.
+// "https://gitlab.example.com"; a trailing "/api/v4" is accepted) without
+// reading any environment variables. A project is required:
+//
+//	c, err := gitlab.New("https://gitlab.example.com",
+//		gitlab.WithToken(token), gitlab.WithProject("group/app"))
+//
+// Defaults: 10s timeout, DefaultRetryPolicy, DefaultUserAgent, log.Printf.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/api/v4")
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, errors.New("invalid GitLab base URL: " + err.Error())
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retry:      DefaultRetryPolicy,
+		userAgent:  DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeout > 0 {
+		// Copy so a caller-supplied client isn't mutated.
+		hc := *c.httpClient
+		hc.Timeout = c.timeout
+		c.httpClient = &hc
+	}
+	if c.projectID == "" {
+		return nil, errors.New("gitlab.New: a project is required (WithProject)")
+	}
+
+	c.initServices()
+	return c, nil
+}
+
+// WithToken authenticates with a personal/project/group access token.
+func WithToken(token string) Option {
+	return func(c *Client) { c.auth = append(c.auth, PrivateToken(token)) }
+}
+
+// WithAuth appends authenticators, tried in order (see Authenticator).
+func WithAuth(auths ...Authenticator) Option {
+	return func(c *Client) { c.auth = append(c.auth, auths...) }
+}
+
+// WithProject sets the project (numeric ID or "group/project" path).
+func WithProject(project string) Option {
+	return func(c *Client) { c.projectID = project }
+}
+
+// WithHTTPClient replaces the default HTTP client (10s timeout).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithTimeout sets the per-request HTTP timeout (also on a WithHTTPClient client).
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy; RetryPolicy{} disables retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithLogger sets where the client logs retries and auth fallbacks.
+func WithLogger(logger func(string, ...any)) Option {
+	return func(c *Client) { c.logger = logger }
+}
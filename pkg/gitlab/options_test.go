@@ -0,0 +1,48 @@
+package gitlab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewAppliesOptions(t *testing.T) {
+	var gotUA, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA, gotPath = r.UserAgent(), r.URL.EscapedPath()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	hc := &http.Client{}
+	c, err := New(srv.URL+"/api/v4/",
+		WithToken("t"),
+		WithProject("group/app"),
+		WithHTTPClient(hc),
+		WithTimeout(3*time.Second),
+		WithUserAgent("release-tool/1.0"),
+		WithRetryPolicy(RetryPolicy{}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if hc.Timeout != 0 || c.httpClient.Timeout != 3*time.Second {
+		t.Errorf("timeout: caller client %s, client %s; want 0 and 3s", hc.Timeout, c.httpClient.Timeout)
+	}
+	if _, err := c.Commits.GetCommit("abc"); err != nil {
+		t.Fatalf("GetCommit: %v", err)
+	}
+	if gotUA != "release-tool/1.0" || gotPath != "/api/v4/projects/group%2Fapp/repository/commits/abc" {
+		t.Errorf("got UA %q path %q", gotUA, gotPath)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New("https://gitlab.example.com", WithToken("t")); err == nil {
+		t.Error("New without a project should fail")
+	}
+	if _, err := New("not a url", WithProject("1")); err == nil {
+		t.Error("New with an invalid base URL should fail")
+	}
+}
@@ -0,0 +1,125 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultPerPage is the page size requested when a list path doesn't set one.
+// GitLab caps per_page at 100 (default 20).
+const defaultPerPage = "100"
+
+// Paginate iterates over every item of a GitLab list endpoint, fetching pages
+// lazily. The next page is taken from the Link rel="next" header (which also
+// covers keyset pagination, e.g. "?pagination=keyset&order_by=name") and
+// falls back to X-Next-Page for offset pagination.
+// Breaking out of the loop stops fetching; an error ends the iteration.
+//
+//	for tag, err := range gitlab.Paginate[Tag](c, path) { ... }
+func Paginate[T any](c *Client, path string) iter.Seq2[T, error] {
+	return PaginateCtx[T](context.Background(), c, path)
+}
+
+// PaginateCtx is Paginate under an explicit context.
+func PaginateCtx[T any](ctx context.Context, c *Client, path string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		next := withPerPage(path)
+		for next != "" {
+			data, header, err := c.doRequest(ctx, "GET", next, nil)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			var items []T
+			if err := json.Unmarshal(data, &items); err != nil {
+				yield(zero, fmt.Errorf("failed to parse page %s: %w", next, err))
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			next = nextPagePath(next, header)
+		}
+	}
+}
+
+// ListAll collects every item of a GitLab list endpoint (see Paginate).
+func ListAll[T any](c *Client, path string) ([]T, error) {
+	return ListAllCtx[T](context.Background(), c, path)
+}
+
+// ListAllCtx is ListAll under an explicit context.
+func ListAllCtx[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var out []T
+	for item, err := range PaginateCtx[T](ctx, c, path) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// withPerPage adds per_page=defaultPerPage unless the path already sets it.
+func withPerPage(path string) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil || q.Has("per_page") {
+		return path
+	}
+	q.Set("per_page", defaultPerPage)
+	return base + "?" + q.Encode()
+}
+
+// nextPagePath returns the API-relative path of the page after current, or ""
+// on the last page.
+func nextPagePath(current string, header http.Header) string {
+	if link := nextLink(header.Get("Link")); link != "" {
+		if u, err := url.Parse(link); err == nil {
+			p := u.EscapedPath()
+			if i := strings.Index(p, "/api/v4"); i >= 0 {
+				p = p[i+len("/api/v4"):]
+			}
+			if u.RawQuery != "" {
+				p += "?" + u.RawQuery
+			}
+			return p
+		}
+	}
+
+	page := strings.TrimSpace(header.Get("X-Next-Page"))
+	if page == "" {
+		return ""
+	}
+	base, rawQuery, _ := strings.Cut(current, "?")
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ""
+	}
+	q.Set("page", page)
+	return base + "?" + q.Encode()
+}
+
+// nextLink extracts the rel="next" URL from an RFC 8288 Link header.
+func nextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
+		}
+		for _, p := range strings.Split(params, ";") {
+			if strings.ReplaceAll(strings.TrimSpace(p), " ", "") == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
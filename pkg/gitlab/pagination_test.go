@@ -0,0 +1,82 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &Client{baseURL: srv.URL, auth: []Authenticator{PrivateToken("t")}, httpClient: srv.Client(), projectID: "1"}
+}
+
+func TestListAllFollowsXNextPage(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("per_page"); got != defaultPerPage {
+			t.Errorf("per_page = %q, want %q", got, defaultPerPage)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"name":"1.0.0"},{"name":"1.1.0"}]`)
+		case "2":
+			fmt.Fprint(w, `[{"name":"1.2.0"}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	tags, err := ListAll[Tag](c, "/projects/1/repository/tags")
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(tags) != 3 || tags[2].Name != "1.2.0" {
+		t.Fatalf("got %+v, want 3 tags ending in 1.2.0", tags)
+	}
+}
+
+func TestPaginateFollowsLinkAndStopsEarly(t *testing.T) {
+	var requests int
+	var c *Client
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			next := c.baseURL + "/api/v4/projects/1/repository/tags?pagination=keyset&cursor=b"
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+			fmt.Fprint(w, `[{"name":"a"}]`)
+		case "b":
+			w.Header().Set("Link", `<http://unused/api/v4/x?cursor=c>; rel="next"`)
+			fmt.Fprint(w, `[{"name":"b"},{"name":"stop"}]`)
+		default:
+			t.Errorf("fetched past the break: %s", r.URL)
+		}
+	})
+
+	var names []string
+	for tag, err := range Paginate[Tag](c, "/projects/1/repository/tags?pagination=keyset") {
+		if err != nil {
+			t.Fatalf("Paginate: %v", err)
+		}
+		if tag.Name == "stop" {
+			break
+		}
+		names = append(names, tag.Name)
+	}
+	if fmt.Sprint(names) != "[a b]" || requests != 2 {
+		t.Fatalf("names=%v requests=%d, want [a b] after 2 requests", names, requests)
+	}
+}
+
+func TestPaginateSurfacesErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	if _, err := ListAll[Note](c, "/projects/1/merge_requests/2/notes"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,37 @@
+package gitlab_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestProjectScopedView(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.Project = "group/deploy"
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 4}, "")
+
+	service := srv.Client().Project("group/service") // the fake only serves group/deploy
+	deploy := service.Project("group/deploy")
+	if service.ProjectID() != "group/service" || deploy.ProjectID() != "group/deploy" {
+		t.Fatalf("ProjectID: %q / %q", service.ProjectID(), deploy.ProjectID())
+	}
+
+	var gerr *gitlab.GitLabError
+	if err := service.Tags.CreateTag("app-1.0.0", head.ID, ""); !errors.As(err, &gerr) || gerr.StatusCode != http.StatusNotFound {
+		t.Fatalf("service project: err = %v, want 404", err)
+	}
+	if err := deploy.Tags.CreateTag("app-1.0.0", head.ID, ""); err != nil {
+		t.Fatalf("deploy project: CreateTag: %v", err)
+	}
+	if err := deploy.MergeRequests.CreateNote("4", "released app-1.0.0"); err != nil {
+		t.Fatalf("deploy project: CreateNote: %v", err)
+	}
+	if len(srv.Tags()) != 1 || len(srv.Notes(4)) != 1 {
+		t.Fatalf("tags=%v notes=%v", srv.Tags(), srv.Notes(4))
+	}
+}
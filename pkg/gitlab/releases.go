@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,11 @@ import (
 // ReleasesService defines the interface for GitLab Release operations.
 type ReleasesService interface {
 	CreateRelease(payload ReleasePayload) error
+	CreateReleaseCtx(ctx context.Context, payload ReleasePayload) error
 	GetLatestRelease() (Release, error)
+	GetLatestReleaseCtx(ctx context.Context) (Release, error)
+	GetRelease(tagName string) (Release, error)
+	GetReleaseCtx(ctx context.Context, tagName string) (Release, error)
 }
 
 // releasesService is a concrete implementation of ReleasesService.
@@ -18,13 +23,21 @@ type releasesService struct {
 	client *Client
 }
 
-var ErrNoReleases = errors.New("no releases")
+var (
+	ErrNoReleases      = errors.New("no releases")
+	ErrReleaseNotFound = errors.New("release not found")
+)
 
 // CreateRelease creates a new release in the project.
 func (s *releasesService) CreateRelease(payload ReleasePayload) error {
+	return s.CreateReleaseCtx(context.Background(), payload)
+}
+
+// CreateReleaseCtx is CreateRelease under an explicit context.
+func (s *releasesService) CreateReleaseCtx(ctx context.Context, payload ReleasePayload) error {
 	path := fmt.Sprintf("/projects/%s/releases", urlEncode(s.client.projectID))
 
-	_, err := s.client.DoRequest("POST", path, payload)
+	_, err := s.client.DoRequestCtx(ctx, "POST", path, payload)
 	if err != nil {
 		return fmt.Errorf("failed to create release %q: %w", payload.TagName, err)
 	}
@@ -36,6 +49,11 @@ func (s *releasesService) CreateRelease(payload ReleasePayload) error {
 // GetLatestRelease returns the most-recent release (by created_at).
 // Safe against nil receiver/client and projects with zero releases.
 func (s *releasesService) GetLatestRelease() (Release, error) {
+	return s.GetLatestReleaseCtx(context.Background())
+}
+
+// GetLatestReleaseCtx is GetLatestRelease under an explicit context.
+func (s *releasesService) GetLatestReleaseCtx(ctx context.Context) (Release, error) {
 	if s == nil || s.client == nil {
 		return Release{}, fmt.Errorf("GetLatestRelease: nil client")
 	}
@@ -49,7 +67,7 @@ func (s *releasesService) GetLatestRelease() (Release, error) {
 		urlEncode(pid),
 	)
 
-	respData, err := s.client.DoRequest("GET", path, nil)
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
 	if err != nil {
 		// Normalize "no releases yet" to a sentinel instead of surfacing a 404
 		if gerr, ok := err.(*GitLabError); ok && gerr.StatusCode == 404 {
@@ -67,3 +85,30 @@ func (s *releasesService) GetLatestRelease() (Release, error) {
 	}
 	return releases[0], nil
 }
+
+// GetRelease fetches the release for a tag. Returns ErrReleaseNotFound on 404.
+func (s *releasesService) GetRelease(tagName string) (Release, error) {
+	return s.GetReleaseCtx(context.Background(), tagName)
+}
+
+// GetReleaseCtx is GetRelease under an explicit context.
+func (s *releasesService) GetReleaseCtx(ctx context.Context, tagName string) (Release, error) {
+	if s == nil || s.client == nil {
+		return Release{}, fmt.Errorf("GetRelease: nil client")
+	}
+	path := fmt.Sprintf("/projects/%s/releases/%s", urlEncode(s.client.projectID), urlEncode(tagName))
+
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
+	if err != nil {
+		if gerr, ok := err.(*GitLabError); ok && gerr.StatusCode == 404 {
+			return Release{}, ErrReleaseNotFound
+		}
+		return Release{}, fmt.Errorf("GetRelease: fetch failed: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(respData, &release); err != nil {
+		return Release{}, fmt.Errorf("GetRelease: unmarshal failed: %w", err)
+	}
+	return release, nil
+}
@@ -2,6 +2,9 @@
 package gitlab
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -29,12 +32,39 @@ type UpdateFileOptions struct {
 	LastCommitID string
 }
 
+// FileAction is one file change in a multi-file commit.
+type FileAction struct {
+	Action   string `json:"action"` // create | update | delete | move
+	FilePath string `json:"file_path"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // "text" (default) or "base64"
+	// LastCommitID, when set, makes GitLab reject the commit if the file was
+	// changed by any later commit (see RepoFile.LastCommitID).
+	LastCommitID string `json:"last_commit_id,omitempty"`
+}
+
+// RepoFile is a repository file at a ref, with its content decoded.
+type RepoFile struct {
+	FilePath     string `json:"file_path"`
+	Content      string `json:"content"`
+	Encoding     string `json:"encoding"`
+	CommitID     string `json:"commit_id"`
+	LastCommitID string `json:"last_commit_id"` // last commit that changed the file
+}
+
 // ---------- Service ----------
 
 type RepoFilesService interface {
+	GetFile(filePath, ref string) (RepoFile, error)
+	GetFileCtx(ctx context.Context, filePath, ref string) (RepoFile, error)
 	CreateFile(filePath string, opts CreateFileOptions) error
+	CreateFileCtx(ctx context.Context, filePath string, opts CreateFileOptions) error
 	UpdateFile(filePath string, opts UpdateFileOptions) error
+	UpdateFileCtx(ctx context.Context, filePath string, opts UpdateFileOptions) error
 	UpsertFile(filePath string, branch, commitMessage, content string) error
+	UpsertFileCtx(ctx context.Context, filePath string, branch, commitMessage, content string) error
+	CommitFiles(branch, commitMessage string, actions []FileAction) (Commit, error)
+	CommitFilesCtx(ctx context.Context, branch, commitMessage string, actions []FileAction) (Commit, error)
 }
 
 type repoFilesService struct {
@@ -43,7 +73,45 @@ type repoFilesService struct {
 
 // ---------- Public methods ----------
 
+// GetFile fetches filePath at ref (branch, tag or SHA).
+func (s *repoFilesService) GetFile(filePath, ref string) (RepoFile, error) {
+	return s.GetFileCtx(context.Background(), filePath, ref)
+}
+
+// GetFileCtx is GetFile under an explicit context.
+func (s *repoFilesService) GetFileCtx(ctx context.Context, filePath, ref string) (RepoFile, error) {
+	if strings.TrimSpace(filePath) == "" || strings.TrimSpace(ref) == "" {
+		return RepoFile{}, wrap("GetFile", fmt.Errorf("filePath and ref are required"))
+	}
+	path := fmt.Sprintf("/projects/%s/repository/files/%s?ref=%s",
+		urlEncode(s.client.projectID),
+		url.PathEscape(filePath),
+		url.QueryEscape(ref),
+	)
+	respData, err := s.client.DoRequestCtx(ctx, "GET", path, nil)
+	if err != nil {
+		return RepoFile{}, fmt.Errorf("GetFile: GET %s failed: %w", path, err)
+	}
+	var file RepoFile
+	if err := json.Unmarshal(respData, &file); err != nil {
+		return RepoFile{}, fmt.Errorf("GetFile: unmarshal: %w", err)
+	}
+	if file.Encoding == "base64" {
+		content, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return RepoFile{}, fmt.Errorf("GetFile: decode %s: %w", filePath, err)
+		}
+		file.Content, file.Encoding = string(content), "text"
+	}
+	return file, nil
+}
+
 func (s *repoFilesService) CreateFile(filePath string, opts CreateFileOptions) error {
+	return s.CreateFileCtx(context.Background(), filePath, opts)
+}
+
+// CreateFileCtx is CreateFile under an explicit context.
+func (s *repoFilesService) CreateFileCtx(ctx context.Context, filePath string, opts CreateFileOptions) error {
 	if err := validatePathBranchMsg(filePath, opts.Branch, opts.CommitMessage); err != nil {
 		return wrap("CreateFile", err)
 	}
@@ -57,13 +125,18 @@ func (s *repoFilesService) CreateFile(filePath string, opts CreateFileOptions) e
 		url.PathEscape(filePath),
 	)
 
-	if _, err := s.client.DoRequest("POST", path, body); err != nil {
+	if _, err := s.client.DoRequestCtx(ctx, "POST", path, body); err != nil {
 		return fmt.Errorf("CreateFile: POST %s failed: %w", path, err)
 	}
 	return nil
 }
 
 func (s *repoFilesService) UpdateFile(filePath string, opts UpdateFileOptions) error {
+	return s.UpdateFileCtx(context.Background(), filePath, opts)
+}
+
+// UpdateFileCtx is UpdateFile under an explicit context.
+func (s *repoFilesService) UpdateFileCtx(ctx context.Context, filePath string, opts UpdateFileOptions) error {
 	if err := validatePathBranchMsg(filePath, opts.Branch, opts.CommitMessage); err != nil {
 		return wrap("UpdateFile", err)
 	}
@@ -77,7 +150,7 @@ func (s *repoFilesService) UpdateFile(filePath string, opts UpdateFileOptions) e
 		url.PathEscape(filePath),
 	)
 
-	if _, err := s.client.DoRequest("PUT", path, body); err != nil {
+	if _, err := s.client.DoRequestCtx(ctx, "PUT", path, body); err != nil {
 		return fmt.Errorf("UpdateFile: PUT %s failed: %w", path, err)
 	}
 	return nil
@@ -86,6 +159,11 @@ func (s *repoFilesService) UpdateFile(filePath string, opts UpdateFileOptions) e
 // UpsertFile creates the file if missing; otherwise updates it.
 // Uses status-code detection when available; falls back to substring check.
 func (s *repoFilesService) UpsertFile(filePath, branch, commitMessage, content string) error {
+	return s.UpsertFileCtx(context.Background(), filePath, branch, commitMessage, content)
+}
+
+// UpsertFileCtx is UpsertFile under an explicit context.
+func (s *repoFilesService) UpsertFileCtx(ctx context.Context, filePath, branch, commitMessage, content string) error {
 	create := CreateFileOptions{
 		fileBaseOpts: fileBaseOpts{
 			Branch:        branch,
@@ -94,7 +172,7 @@ func (s *repoFilesService) UpsertFile(filePath, branch, commitMessage, content s
 			Encoding:      "text",
 		},
 	}
-	if err := s.CreateFile(filePath, create); err == nil {
+	if err := s.CreateFileCtx(ctx, filePath, create); err == nil {
 		return nil
 	} else {
 		// Prefer typed/status error if your client supports it.
@@ -107,12 +185,48 @@ func (s *repoFilesService) UpsertFile(filePath, branch, commitMessage, content s
 					Encoding:      "text",
 				},
 			}
-			return s.UpdateFile(filePath, update)
+			return s.UpdateFileCtx(ctx, filePath, update)
 		}
 		return err
 	}
 }
 
+// CommitFiles applies all actions in a single commit on branch (atomic: either
+// every file changes or none does).
+func (s *repoFilesService) CommitFiles(branch, commitMessage string, actions []FileAction) (Commit, error) {
+	return s.CommitFilesCtx(context.Background(), branch, commitMessage, actions)
+}
+
+// CommitFilesCtx is CommitFiles under an explicit context.
+func (s *repoFilesService) CommitFilesCtx(ctx context.Context, branch, commitMessage string, actions []FileAction) (Commit, error) {
+	if strings.TrimSpace(branch) == "" {
+		return Commit{}, wrap("CommitFiles", fmt.Errorf("branch is required"))
+	}
+	if strings.TrimSpace(commitMessage) == "" {
+		return Commit{}, wrap("CommitFiles", fmt.Errorf("commit message is required"))
+	}
+	if len(actions) == 0 {
+		return Commit{}, wrap("CommitFiles", fmt.Errorf("at least one action is required"))
+	}
+
+	path := fmt.Sprintf("/projects/%s/repository/commits", urlEncode(s.client.projectID))
+	body := map[string]any{
+		"branch":         branch,
+		"commit_message": commitMessage,
+		"actions":        actions,
+	}
+
+	respData, err := s.client.DoRequestCtx(ctx, "POST", path, body)
+	if err != nil {
+		return Commit{}, fmt.Errorf("CommitFiles: POST %s failed: %w", path, err)
+	}
+	var commit Commit
+	if err := json.Unmarshal(respData, &commit); err != nil {
+		return Commit{}, fmt.Errorf("CommitFiles: unmarshal: %w", err)
+	}
+	return commit, nil
+}
+
 // ---------- Helpers ----------
 
 func validatePathBranchMsg(path, branch, msg string) error {
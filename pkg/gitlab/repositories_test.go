@@ -0,0 +1,32 @@
+package gitlab_test
+
+import (
+	"testing"
+
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestCommitFilesRejectsStaleUpdate(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	srv.AddCommit(gitlab.Commit{Message: "init"}, "VERSION")
+	srv.SetFile("main", "VERSION", "1.0.0\n")
+	files := srv.Client().Repositories
+
+	file, err := files.GetFile("VERSION", "main")
+	if err != nil || file.Content != "1.0.0\n" || file.LastCommitID == "" {
+		t.Fatalf("GetFile = %+v, %v", file, err)
+	}
+
+	// Someone else changes the file after we read it.
+	srv.AddCommit(gitlab.Commit{Message: "fix: bump by hand"}, "VERSION")
+	srv.SetFile("main", "VERSION", "1.0.1\n")
+
+	stale := []gitlab.FileAction{{Action: "update", FilePath: "VERSION", Content: "1.1.0\n", LastCommitID: file.LastCommitID}}
+	if _, err := files.CommitFiles("main", "chore: sync", stale); err == nil {
+		t.Fatal("CommitFiles with a stale last_commit_id should fail")
+	}
+	if got, _ := srv.File("main", "VERSION"); got != "1.0.1\n" {
+		t.Fatalf("VERSION = %q, want the newer edit kept", got)
+	}
+}
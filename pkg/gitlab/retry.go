@@ -0,0 +1,126 @@
+package gitlab
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how DoRequest retries transient failures: transport
+// errors, 429 and 5xx gateway/availability errors.
+type RetryPolicy struct {
+	MaxRetries int           // extra attempts after the first; 0 disables retries
+	BaseDelay  time.Duration // first backoff, doubled on every attempt (with jitter)
+	MaxDelay   time.Duration // cap for backoff and for server-requested waits
+
+	// RetryNonIdempotent also retries POST/PATCH. Off by default: a request that
+	// timed out may still have been applied (e.g. a created tag or note).
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is used by NewClient unless overridden by environment.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// retryPolicyFromEnv applies the optional GITLAB_CLIENT_* retry overrides:
+//   - GITLAB_CLIENT_MAX_RETRIES (e.g. "5", "0" disables retries)
+//   - GITLAB_CLIENT_RETRY_BASE_DELAY / GITLAB_CLIENT_RETRY_MAX_DELAY (e.g. "1s")
+//   - GITLAB_CLIENT_RETRY_NON_IDEMPOTENT=true
+func retryPolicyFromEnv(p RetryPolicy) RetryPolicy {
+	if n, err := strconv.Atoi(os.Getenv("GITLAB_CLIENT_MAX_RETRIES")); err == nil && n >= 0 {
+		p.MaxRetries = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("GITLAB_CLIENT_RETRY_BASE_DELAY")); err == nil && d > 0 {
+		p.BaseDelay = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("GITLAB_CLIENT_RETRY_MAX_DELAY")); err == nil && d > 0 {
+		p.MaxDelay = d
+	}
+	if os.Getenv("GITLAB_CLIENT_RETRY_NON_IDEMPOTENT") == "true" {
+		p.RetryNonIdempotent = true
+	}
+	return p
+}
+
+// shouldRetry reports whether a failed attempt of method may be retried.
+func (p RetryPolicy) shouldRetry(method string, err error) bool {
+	if !p.RetryNonIdempotent && !isIdempotent(method) {
+		return false
+	}
+	if errors.Is(err, errAuthUnavailable) {
+		return false
+	}
+	var gerr *GitLabError
+	if !errors.As(err, &gerr) {
+		return true // transport error: connection reset, timeout, ...
+	}
+	switch gerr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// delay returns how long to wait before retry number attempt+1. A server hint
+// (Retry-After, or RateLimit-Reset once the budget is spent) wins over the
+// exponential backoff; both are capped at MaxDelay.
+func (p RetryPolicy) delay(attempt int, err error, now time.Time) time.Duration {
+	var gerr *GitLabError
+	if errors.As(err, &gerr) {
+		if d, ok := serverDelay(gerr.Header, now); ok {
+			return min(d, p.maxDelay())
+		}
+	}
+	backoff := p.BaseDelay << attempt
+	if backoff <= 0 || backoff > p.maxDelay() {
+		backoff = p.maxDelay()
+	}
+	// Equal jitter: half fixed, half random, so concurrent jobs spread out.
+	half := backoff / 2
+	return half + rand.N(half+1)
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return DefaultRetryPolicy.MaxDelay
+}
+
+// serverDelay reads Retry-After (seconds or HTTP date) or, when
+// RateLimit-Remaining is 0, RateLimit-Reset (unix seconds).
+func serverDelay(h http.Header, now time.Time) (time.Duration, bool) {
+	if h == nil {
+		return 0, false
+	}
+	if ra := strings.TrimSpace(h.Get("Retry-After")); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(ra); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+	if strings.TrimSpace(h.Get("RateLimit-Remaining")) == "0" {
+		if reset, err := strconv.ParseInt(strings.TrimSpace(h.Get("RateLimit-Reset")), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0), true
+		}
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// retryClient returns a test client that records retry waits instead of sleeping.
+func retryClient(t *testing.T, policy RetryPolicy, h http.HandlerFunc) (*Client, *[]time.Duration) {
+	t.Helper()
+	c := newTestClient(t, h)
+	var waits []time.Duration
+	c.retry = policy
+	c.sleepFn = func(d time.Duration) { waits = append(waits, d) }
+	c.logger = t.Logf
+	return c, &waits
+}
+
+func TestDoRequestRetriesTransientErrors(t *testing.T) {
+	calls := 0
+	c, waits := retryClient(t, RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls < 3 {
+				http.Error(w, "busy", http.StatusBadGateway)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		})
+
+	if _, err := c.DoRequest("GET", "/projects/1", nil); err != nil {
+		t.Fatalf("DoRequest: %v", err)
+	}
+	if calls != 3 || len(*waits) != 2 {
+		t.Fatalf("calls=%d waits=%v, want 3 calls and 2 waits", calls, *waits)
+	}
+	// Equal jitter keeps each wait within [backoff/2, backoff].
+	for i, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if w := (*waits)[i]; w < want/2 || w > want {
+			t.Errorf("wait %d = %s, want within [%s, %s]", i, w, want/2, want)
+		}
+	}
+}
+
+func TestDoRequestHonoursRetryAfter(t *testing.T) {
+	calls := 0
+	c, waits := retryClient(t, RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Minute},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.Header().Set("Retry-After", "7")
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		})
+
+	if _, err := c.DoRequest("GET", "/projects/1", nil); err != nil {
+		t.Fatalf("DoRequest: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("waits=%v, want [7s]", *waits)
+	}
+}
+
+func TestDoRequestGivesUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		policy RetryPolicy
+		calls  int
+	}{
+		{"exhausted", "GET", http.StatusServiceUnavailable, RetryPolicy{MaxRetries: 2}, 3},
+		{"not transient", "GET", http.StatusNotFound, RetryPolicy{MaxRetries: 2}, 1},
+		{"non-idempotent", "POST", http.StatusServiceUnavailable, RetryPolicy{MaxRetries: 2}, 1},
+		{"non-idempotent opt-in", "POST", http.StatusServiceUnavailable, RetryPolicy{MaxRetries: 2, RetryNonIdempotent: true}, 3},
+		{"disabled", "GET", http.StatusServiceUnavailable, RetryPolicy{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			c, _ := retryClient(t, tt.policy, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				http.Error(w, "nope", tt.status)
+			})
+			_, err := c.DoRequest(tt.method, "/projects/1/repository/tags", map[string]string{"tag_name": "1.0.0"})
+			var gerr *GitLabError
+			if !errors.As(err, &gerr) || gerr.StatusCode != tt.status {
+				t.Fatalf("err = %v, want GitLabError %d", err, tt.status)
+			}
+			if calls != tt.calls {
+				t.Fatalf("calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestServerDelayRateLimitReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	h := http.Header{}
+	h.Set("RateLimit-Remaining", "0")
+	h.Set("RateLimit-Reset", "1700000012")
+	if d, ok := serverDelay(h, now); !ok || d != 12*time.Second {
+		t.Fatalf("serverDelay = %s, %v; want 12s, true", d, ok)
+	}
+	h.Set("RateLimit-Remaining", "5")
+	if _, ok := serverDelay(h, now); ok {
+		t.Fatal("serverDelay should ignore RateLimit-Reset while budget remains")
+	}
+}
+
+func TestCtxMethodsAbortRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	c, _ := retryClient(t, RetryPolicy{MaxRetries: 5}, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		cancel() // the job is cancelled while the first request is in flight
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	})
+	c.initServices()
+
+	_, err := c.Tags.ListProjectTagsCtx(ctx)
+	if err == nil || calls != 1 {
+		t.Fatalf("err=%v calls=%d, want an error after 1 call", err, calls)
+	}
+}
+
+func TestCtxDeadlineCutsRetryWait(t *testing.T) {
+	c, _ := retryClient(t, RetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	})
+	c.sleepFn = nil // really wait, so only the deadline can end it
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.DoRequestCtx(ctx, "GET", "/projects/1", nil)
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Fatalf("err = %v, want the retry wait cut by the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("took %s, want the 60s retry wait abandoned at the deadline", elapsed)
+	}
+}
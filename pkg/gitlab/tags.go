@@ -1,73 +1,216 @@
 package gitlab
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"sort"
+
 	"syac/internal/version"
 )
 
 type TagsService interface {
 	ListProjectTags() ([]Tag, error)
+	ListProjectTagsCtx(ctx context.Context) ([]Tag, error)
 	GetLatestTag() (version.Version, error)
+	GetLatestTagCtx(ctx context.Context) (version.Version, error)
 	CreateTag(tagName, ref, message string) error
+	CreateTagCtx(ctx context.Context, tagName, ref, message string) error
 	GetNextVersion(bump version.VersionType) (version.Version, version.Version, error)
+	GetNextVersionCtx(ctx context.Context, bump version.VersionType) (version.Version, version.Version, error)
+	NextVersion(current version.Version, bump version.VersionType) version.Version
+	GetNextPreRelease(base version.Version, id string) (version.Version, error)
+	GetNextPreReleaseCtx(ctx context.Context, base version.Version, id string) (version.Version, error)
+	WithFormat(format version.TagFormat) TagsService
+	WithLine(line version.Line) TagsService
+	GetReleaseTagForCommit(sha string) (Tag, version.Version, error)
+	GetReleaseTagForCommitCtx(ctx context.Context, sha string) (Tag, version.Version, error)
 }
 
+var (
+	// ErrTagNotFound is returned when a tag lookup has no match.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrNoTags is returned by GetLatestTag when the project has no tags of the
+	// format (and line) yet. Any other error means the tags could not be listed.
+	ErrNoTags = errors.New("no version tags yet")
+)
+
 type tagsService struct {
 	client *Client
+	format version.TagFormat // zero value == version.DefaultTagFormat
+	line   *version.Line     // nil == all release lines
+}
+
+// WithFormat returns a TagsService that only recognizes tags rendered by format
+// (e.g. "v{{.Version}}"). The zero TagFormat is the bare "1.2.3" style.
+func (s *tagsService) WithFormat(format version.TagFormat) TagsService {
+	return &tagsService{client: s.client, format: format, line: s.line}
 }
 
-// ListProjectTags retrieves all tags in the current project.
+// WithLine returns a TagsService that only considers tags of one MAJOR.MINOR
+// line, e.g. 1.4.x for a release/1.4 maintenance branch.
+func (s *tagsService) WithLine(line version.Line) TagsService {
+	return &tagsService{client: s.client, format: s.format, line: &line}
+}
+
+// ListProjectTags retrieves all tags in the current project, across all pages.
 // If the project has no tags, it returns an empty slice.
 func (s *tagsService) ListProjectTags() ([]Tag, error) {
+	return s.ListProjectTagsCtx(context.Background())
+}
+
+// ListProjectTagsCtx is ListProjectTags under an explicit context.
+func (s *tagsService) ListProjectTagsCtx(ctx context.Context) ([]Tag, error) {
 	path := fmt.Sprintf("/projects/%s/repository/tags", urlEncode(s.client.projectID))
-	respData, err := s.client.DoRequest("GET", path, nil)
+	tags, err := ListAllCtx[Tag](ctx, s.client, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch tags: %w", err)
 	}
-
-	var tags []Tag
-	if err := json.Unmarshal(respData, &tags); err != nil {
-		return nil, fmt.Errorf("failed to parse tag list: %w", err)
-	}
 	return tags, nil
 }
 
-// GetLatestTag finds the highest SemVer-compliant tag from the repo.
-// Rejects tags with "v" prefix (e.g. "v1.2.3"). If no valid tags exist,
-// it defaults to version 0.0.0.
+// GetLatestTag finds the highest version tag from the repo, ordered by the tag
+// format's scheme (SemVer 2.0 precedence by default). Pre-release tags (e.g.
+// "1.4.0-rc.2") are considered, so a pending RC forecasts its own release. Only
+// tags matching the service's tag format count; with the default format,
+// "v1.2.3" is rejected (see WithFormat).
+//
+// Candidates are fetched newest-version-first (order_by=version) and narrowed
+// with search, so usually only the first page is read. If no valid tags exist
+// it returns 0.0.0 and ErrNoTags; listing failures are returned as-is.
 func (s *tagsService) GetLatestTag() (version.Version, error) {
-	tags, err := s.ListProjectTags()
-	if err != nil {
-		return version.Version{Major: 0, Minor: 0, Patch: 0}, nil
+	return s.GetLatestTagCtx(context.Background())
+}
+
+// GetLatestTagCtx is GetLatestTag under an explicit context.
+func (s *tagsService) GetLatestTagCtx(ctx context.Context) (version.Version, error) {
+	q := url.Values{}
+	q.Set("order_by", "version")
+	q.Set("sort", "desc")
+	if search := s.searchPrefix(); search != "" {
+		q.Set("search", "^"+search)
 	}
+	path := fmt.Sprintf("/projects/%s/repository/tags?%s", urlEncode(s.client.projectID), q.Encode())
 
-	var parsed []version.Version
-	for _, tag := range tags {
-		v, perr := version.Parse(tag.Name)
+	scheme := s.format.Scheme()
+	var (
+		best  version.Version
+		found bool
+	)
+	for tag, err := range PaginateCtx[Tag](ctx, s.client, path) {
+		if err != nil {
+			var gerr *GitLabError
+			if errors.As(err, &gerr) && gerr.StatusCode == http.StatusBadRequest && !found {
+				// Older GitLab without order_by=version: sort client-side.
+				return s.latestFromList(ctx)
+			}
+			return version.Version{}, fmt.Errorf("failed to fetch tags: %w", err)
+		}
+		v, perr := s.format.Parse(tag.Name)
 		if perr != nil {
-			// Ignore non-SemVer (including "v1.2.3")
+			// Ignore non-version tags and tags of other formats
 			continue
 		}
-		parsed = append(parsed, v)
+		if s.line != nil && !s.line.Contains(v) {
+			continue
+		}
+		// Version order is descending by numeric core; once we pass below the
+		// best core, nothing further can win. Tags sharing a core (1.4.3 and
+		// its pre-releases) are adjacent, in whatever order the server chose.
+		if found && scheme.Compare(core(v), core(best)) < 0 {
+			break
+		}
+		if !found || scheme.Compare(v, best) > 0 {
+			best, found = v, true
+		}
+	}
+	if !found {
+		return version.Version{}, ErrNoTags
 	}
+	return best, nil
+}
 
+// latestFromList is GetLatestTag over the full, client-side sorted tag list.
+func (s *tagsService) latestFromList(ctx context.Context) (version.Version, error) {
+	parsed, err := s.listVersions(ctx, "")
+	if err != nil {
+		return version.Version{}, err
+	}
 	if len(parsed) == 0 {
-		return version.Version{Major: 0, Minor: 0, Patch: 0}, nil
+		return version.Version{}, ErrNoTags
 	}
 
-	// Sort ascending and return the highest
+	// Sort ascending (by the format's scheme) and return the highest
+	scheme := s.format.Scheme()
 	sort.Slice(parsed, func(i, j int) bool {
-		return parsed[i].LessThan(parsed[j])
+		return scheme.Compare(parsed[i], parsed[j]) < 0
 	})
 	return parsed[len(parsed)-1], nil
 }
 
+// searchPrefix is the literal tag-name prefix every candidate shares: the
+// format prefix, plus "MAJOR.MINOR." on a SemVer release line.
+func (s *tagsService) searchPrefix() string {
+	prefix := s.format.Prefix()
+	if _, ok := s.format.Scheme().(version.SemVerScheme); ok && s.line != nil {
+		prefix += fmt.Sprintf("%d.%d.", s.line.Major, s.line.Minor)
+	}
+	return prefix
+}
+
+// core drops pre-release and build metadata.
+func core(v version.Version) version.Version {
+	return version.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// listVersions returns the version of every tag matching the tag format (and
+// release line, if set), unsorted. A non-empty search narrows the listing
+// server-side to tags starting with that literal text.
+func (s *tagsService) listVersions(ctx context.Context, search string) ([]version.Version, error) {
+	var (
+		tags []Tag
+		err  error
+	)
+	if search == "" {
+		tags, err = s.ListProjectTagsCtx(ctx)
+	} else {
+		q := url.Values{}
+		q.Set("search", "^"+search)
+		path := fmt.Sprintf("/projects/%s/repository/tags?%s", urlEncode(s.client.projectID), q.Encode())
+		if tags, err = ListAllCtx[Tag](ctx, s.client, path); err != nil {
+			err = fmt.Errorf("failed to fetch tags: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []version.Version
+	for _, tag := range tags {
+		v, perr := s.format.Parse(tag.Name)
+		if perr != nil {
+			// Ignore non-version tags and tags of other formats
+			continue
+		}
+		if s.line != nil && !s.line.Contains(v) {
+			continue
+		}
+		parsed = append(parsed, v)
+	}
+	return parsed, nil
+}
+
 // CreateTag creates a new Git tag for the given ref and optional message.
 // Also sets SYAC_TAG in the environment for downstream jobs.
 func (s *tagsService) CreateTag(tagName, ref, message string) error {
+	return s.CreateTagCtx(context.Background(), tagName, ref, message)
+}
+
+// CreateTagCtx is CreateTag under an explicit context.
+func (s *tagsService) CreateTagCtx(ctx context.Context, tagName, ref, message string) error {
 	path := fmt.Sprintf("/projects/%s/repository/tags", urlEncode(s.client.projectID))
 
 	payload := map[string]string{
@@ -78,7 +221,7 @@ func (s *tagsService) CreateTag(tagName, ref, message string) error {
 		payload["message"] = message
 	}
 
-	if _, err := s.client.DoRequest("POST", path, payload); err != nil {
+	if _, err := s.client.DoRequestCtx(ctx, "POST", path, payload); err != nil {
 		return fmt.Errorf("failed to create tag %q on ref %q: %w", tagName, ref, err)
 	}
 
@@ -88,10 +231,91 @@ func (s *tagsService) CreateTag(tagName, ref, message string) error {
 	return nil
 }
 
-// GetNextVersion calculates the next version by bump type.
-// If no tags exist, it starts from 0.0.0.
+// GetNextVersion calculates the next version by bump type using the tag
+// format's versioning scheme (SemVer by default; CalVer ignores the bump level).
+// If no tags exist, it starts from 0.0.0 (or MAJOR.MINOR.0 on a release line);
+// a failure to list tags is returned instead of forecasting from 0.0.0.
 func (s *tagsService) GetNextVersion(bump version.VersionType) (version.Version, version.Version, error) {
-	current, _ := s.GetLatestTag()
-	next := current.Increment(bump)
-	return current, next, nil
+	return s.GetNextVersionCtx(context.Background(), bump)
+}
+
+// GetNextVersionCtx is GetNextVersion under an explicit context.
+func (s *tagsService) GetNextVersionCtx(ctx context.Context, bump version.VersionType) (version.Version, version.Version, error) {
+	current, err := s.GetLatestTagCtx(ctx)
+	if err != nil && !errors.Is(err, ErrNoTags) {
+		return version.Version{}, version.Version{}, err
+	}
+	return current, s.NextVersion(current, bump), nil
+}
+
+// NextVersion is the version bump releases after current (as returned by
+// GetLatestTag), without listing tags again.
+func (s *tagsService) NextVersion(current version.Version, bump version.VersionType) version.Version {
+	if s.line != nil && !s.line.Contains(current) {
+		// First release on a new maintenance line: MAJOR.MINOR.0
+		return version.Version{Major: s.line.Major, Minor: s.line.Minor}
+	}
+	return s.format.Scheme().Next(current, bump)
+}
+
+// GetNextPreRelease allocates the next numbered pre-release for base by looking at
+// existing "<base>-<id>.N" tags in the same tag format, e.g. 1.4.3-rc.2 exists -> 1.4.3-rc.3.
+// A failed tag listing is returned as an error so we never hand out an RC number
+// that may already be taken.
+func (s *tagsService) GetNextPreRelease(base version.Version, id string) (version.Version, error) {
+	return s.GetNextPreReleaseCtx(context.Background(), base, id)
+}
+
+// GetNextPreReleaseCtx is GetNextPreRelease under an explicit context.
+func (s *tagsService) GetNextPreReleaseCtx(ctx context.Context, base version.Version, id string) (version.Version, error) {
+	existing, err := s.listVersions(ctx, s.format.Prefix()+s.format.Scheme().Format(base)+"-"+id)
+	if err != nil {
+		return version.Version{}, fmt.Errorf("failed to allocate %s number for %s: %w", id, base, err)
+	}
+	return s.format.Scheme().NextPreRelease(base, id, existing), nil
+}
+
+// GetReleaseTagForCommit returns the highest release (non-pre-release) tag of
+// this format/line that points at sha. Returns ErrTagNotFound when the commit
+// has not been released yet.
+func (s *tagsService) GetReleaseTagForCommit(sha string) (Tag, version.Version, error) {
+	return s.GetReleaseTagForCommitCtx(context.Background(), sha)
+}
+
+// GetReleaseTagForCommitCtx is GetReleaseTagForCommit under an explicit context.
+func (s *tagsService) GetReleaseTagForCommitCtx(ctx context.Context, sha string) (Tag, version.Version, error) {
+	tags, err := s.ListProjectTagsCtx(ctx)
+	if err != nil {
+		return Tag{}, version.Version{}, err
+	}
+
+	var (
+		best  Tag
+		bestV version.Version
+		found bool
+	)
+	scheme := s.format.Scheme()
+	for _, tag := range tags {
+		v, perr := s.format.Parse(tag.Name)
+		if perr != nil || v.IsPreRelease() {
+			continue
+		}
+		if s.line != nil && !s.line.Contains(v) {
+			continue
+		}
+		if !tagOnCommit(tag, sha) {
+			continue
+		}
+		if !found || scheme.Compare(v, bestV) > 0 {
+			best, bestV, found = tag, v, true
+		}
+	}
+	if !found {
+		return Tag{}, version.Version{}, ErrTagNotFound
+	}
+	return best, bestV, nil
+}
+
+func tagOnCommit(tag Tag, sha string) bool {
+	return sha != "" && tag.CommitID() == sha
 }
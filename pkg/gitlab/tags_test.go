@@ -0,0 +1,188 @@
+package gitlab_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"syac/internal/version"
+	"syac/pkg/gitlab"
+	"syac/pkg/gitlab/gitlabtest"
+)
+
+func TestGetNextVersionSeesEveryPage(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	// More tags than fit on GitLab's default page of 20; the highest is created first
+	// so it lands on the last page.
+	srv.AddTag("2.0.0", head.ID)
+	for i := 0; i < 30; i++ {
+		srv.AddTag(fmt.Sprintf("1.%d.0", i), head.ID)
+	}
+	srv.AddTag("not-a-version", head.ID)
+
+	current, next, err := srv.Client().Tags.GetNextVersion(version.Minor)
+	if err != nil {
+		t.Fatalf("GetNextVersion: %v", err)
+	}
+	if current.String() != "2.0.0" || next.String() != "2.1.0" {
+		t.Fatalf("current=%s next=%s, want 2.0.0 -> 2.1.0", current, next)
+	}
+}
+
+func TestGetNextPreRelease(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	for _, name := range []string{"v1.4.2", "v1.4.3-rc.1", "v1.4.3-rc.2", "1.4.3-rc.9"} {
+		srv.AddTag(name, head.ID)
+	}
+	format, err := version.NewTagFormat("v{{.Version}}", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := srv.Client().Tags.WithFormat(format).GetNextPreRelease(version.Version{Major: 1, Minor: 4, Patch: 3}, "rc")
+	if err != nil {
+		t.Fatalf("GetNextPreRelease: %v", err)
+	}
+	if rc.String() != "1.4.3-rc.3" {
+		t.Fatalf("rc = %s, want 1.4.3-rc.3 (other formats ignored)", rc)
+	}
+}
+
+func TestGetReleaseTagForCommit(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	merge := srv.AddCommit(gitlab.Commit{Message: "Merge branch 'feat'"})
+	srv.AddTag("1.0.0-rc.1", merge.ID)
+	// The release tag sits on the version-files commit pushed on top of the merge.
+	files := srv.AddCommit(gitlab.Commit{Message: "chore(release): sync version files to 1.0.0 [skip ci]"})
+	srv.AddTag("1.0.0", files.ID)
+	other := srv.AddCommit(gitlab.Commit{Message: "fix: later"})
+	srv.AddTag("1.0.1", "")
+	tags := srv.Client().Tags
+
+	tag, v, err := tags.GetReleaseTagForCommit(files.ID)
+	if err != nil || tag.Name != "1.0.0" || v.String() != "1.0.0" {
+		t.Errorf("GetReleaseTagForCommit(files) = %q, %s, %v; want 1.0.0", tag.Name, v, err)
+	}
+	// Only the tagged commit itself matches: not the parent of a tagged commit
+	// (the runtime resolves its own version-files commit), and not a pre-release.
+	if _, _, err := tags.GetReleaseTagForCommit(merge.ID); !errors.Is(err, gitlab.ErrTagNotFound) {
+		t.Errorf("GetReleaseTagForCommit(merge): err = %v, want ErrTagNotFound", err)
+	}
+	if tag, _, err := tags.GetReleaseTagForCommit(other.ID); err != nil || tag.Name != "1.0.1" {
+		t.Errorf("GetReleaseTagForCommit(other) = %q, %v; want 1.0.1", tag.Name, err)
+	}
+}
+
+func TestCreateTagConflictAndFault(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	tags := srv.Client().Tags
+
+	if err := tags.CreateTag("1.0.0", head.ID, "Release 1.0.0"); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if err := tags.CreateTag("1.0.0", head.ID, ""); err == nil {
+		t.Fatal("duplicate CreateTag should fail")
+	}
+
+	srv.Inject(gitlabtest.Fault{Method: "POST", Path: "/repository/tags", Status: 500, Times: 1})
+	if err := tags.CreateTag("1.0.1", head.ID, ""); err == nil {
+		t.Fatal("CreateTag should surface the injected 500 (POST is not retried)")
+	}
+	if err := tags.CreateTag("1.0.1", head.ID, ""); err != nil {
+		t.Fatalf("CreateTag after fault: %v", err)
+	}
+	if got := len(srv.Tags()); got != 2 {
+		t.Fatalf("tags = %d, want 2", got)
+	}
+}
+
+func TestGetLatestTagQueriesServerSide(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	for i := 0; i < 50; i++ {
+		srv.AddTag(fmt.Sprintf("api/1.%d.0", i), head.ID)
+	}
+	srv.AddTag("api/1.49.1-rc.1", head.ID)
+	srv.AddTag("web/9.0.0", head.ID)
+	format, err := version.NewTagFormat("{{.App}}/{{.Version}}", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := srv.Client().Tags.WithFormat(format).GetLatestTag()
+	if err != nil || latest.String() != "1.49.1-rc.1" {
+		t.Fatalf("GetLatestTag = %s, %v; want 1.49.1-rc.1", latest, err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || !strings.Contains(reqs[0], "order_by=version") || !strings.Contains(reqs[0], "search=%5Eapi%2F") {
+		t.Fatalf("requests = %v, want one ordered, searched page", reqs)
+	}
+}
+
+func TestGetLatestTagDistinguishesNoTagsFromErrors(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	tags := srv.Client(gitlab.WithRetryPolicy(gitlab.RetryPolicy{})).Tags
+
+	if _, err := tags.GetLatestTag(); !errors.Is(err, gitlab.ErrNoTags) {
+		t.Fatalf("empty project: err = %v, want ErrNoTags", err)
+	}
+	if _, next, err := tags.GetNextVersion(version.Patch); err != nil || next.String() != "0.0.1" {
+		t.Fatalf("empty project: GetNextVersion = %s, %v; want 0.0.1", next, err)
+	}
+
+	srv.Inject(gitlabtest.Fault{Method: "GET", Path: "/repository/tags", Status: 503})
+	_, err := tags.GetLatestTag()
+	if err == nil || errors.Is(err, gitlab.ErrNoTags) {
+		t.Fatalf("outage: err = %v, want a listing error", err)
+	}
+	if _, _, err := tags.GetNextVersion(version.Patch); err == nil {
+		t.Fatal("outage: GetNextVersion must not forecast from 0.0.0")
+	}
+}
+
+func TestGetLatestTagFallsBackWithoutVersionOrder(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	srv.AddTag("1.10.0", head.ID)
+	srv.AddTag("1.9.0", head.ID)
+	// An older GitLab rejects order_by=version.
+	srv.Inject(gitlabtest.Fault{Method: "GET", Path: "/repository/tags", Status: 400, Times: 1})
+
+	latest, err := srv.Client().Tags.GetLatestTag()
+	if err != nil || latest.String() != "1.10.0" {
+		t.Fatalf("GetLatestTag = %s, %v; want 1.10.0", latest, err)
+	}
+}
+
+func TestCtxVariantsPassContextThrough(t *testing.T) {
+	srv := gitlabtest.NewServer(t)
+	head := srv.AddCommit(gitlab.Commit{Message: "init"})
+	srv.AddTag("1.2.3", head.ID)
+	srv.AddMergeRequest(gitlab.MergeRequest{IID: 1, State: "merged"}, "", head.ID)
+	client := srv.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := client.Tags.GetNextVersionCtx(ctx, version.Minor); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetNextVersionCtx: err = %v, want context.Canceled", err)
+	}
+	if _, err := client.MergeRequests.GetMergeRequestForCommitCtx(ctx, head.ID); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetMergeRequestForCommitCtx: err = %v, want context.Canceled", err)
+	}
+	if _, err := client.MergeRequests.ResolveVersionBumpCtx(ctx, "1"); err == nil {
+		t.Error("ResolveVersionBumpCtx: want an error under a cancelled context")
+	}
+	if reqs := srv.Requests(); len(reqs) != 0 {
+		t.Fatalf("requests = %v, want none sent under a cancelled context", reqs)
+	}
+
+	// The plain methods run under context.Background().
+	if _, next, err := client.Tags.GetNextVersion(version.Minor); err != nil || next.String() != "1.3.0" {
+		t.Fatalf("GetNextVersion = %s, %v; want 1.3.0", next, err)
+	}
+}
@@ -1,20 +1,37 @@
 package gitlab
 
 type Tag struct {
-	Name   string `json:"name"`
-	Target string `json:"target,omitempty"` // Commit SHA or tag target
-	WebURL string `json:"web_url,omitempty"`
+	Name   string  `json:"name"`
+	Target string  `json:"target,omitempty"` // Commit SHA or tag target
+	WebURL string  `json:"web_url,omitempty"`
+	Commit *Commit `json:"commit,omitempty"` // commit the tag points to
+}
+
+// CommitID returns the SHA of the tagged commit. For annotated tags Target is the
+// tag object, so the embedded commit wins when GitLab returns it.
+func (t Tag) CommitID() string {
+	if t.Commit != nil && t.Commit.ID != "" {
+		return t.Commit.ID
+	}
+	return t.Target
 }
 
 type MergeRequest struct {
-	IID            int    `json:"iid"`
-	Title          string `json:"title"`
-	MergeCommitSHA string `json:"merge_commit_sha"`
-	State          string `json:"state,omitempty"` // opened, closed, merged
-	WebURL         string `json:"web_url,omitempty"`
-
-	SourceBranch string `json:"source_branch"`
-	TargetBranch string `json:"target_branch"`
+	IID             int    `json:"iid"`
+	Title           string `json:"title"`
+	MergeCommitSHA  string `json:"merge_commit_sha"`
+	SquashCommitSHA string `json:"squash_commit_sha,omitempty"`
+	State           string `json:"state,omitempty"` // opened, closed, merged
+	WebURL          string `json:"web_url,omitempty"`
+
+	SourceBranch string   `json:"source_branch"`
+	TargetBranch string   `json:"target_branch"`
+	Labels       []string `json:"labels,omitempty"`
+}
+
+// landedAs reports whether the MR was merged (or squashed) into sha.
+func (mr MergeRequest) landedAs(sha string) bool {
+	return sha != "" && (mr.MergeCommitSHA == sha || mr.SquashCommitSHA == sha)
 }
 
 type ProtectedBranch struct {
@@ -1,31 +1,52 @@
+// Sample: forecast the next version of a project with the options-based client.
+//
+//	go run ./samples -url https://gitlab.example.com -project group/app -token $TOKEN -bump minor
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"time"
 
 	"syac/internal/version"
 	"syac/pkg/gitlab"
 )
 
 func main() {
-	client, err := gitlab.NewClient()
+	baseURL := flag.String("url", "https://gitlab.com", "GitLab base URL")
+	project := flag.String("project", "", "project ID or path, e.g. group/app")
+	token := flag.String("token", "", "personal/project access token")
+	bumpFlag := flag.String("bump", "patch", "bump type: patch | minor | major")
+	flag.Parse()
+
+	client, err := gitlab.New(*baseURL,
+		gitlab.WithToken(*token),
+		gitlab.WithProject(*project),
+		gitlab.WithTimeout(30*time.Second),
+		gitlab.WithUserAgent("syac-sample"),
+	)
 	if err != nil {
 		log.Fatalf("[gitlab] init failed: %v", err)
 	}
 
-	// (Optional) Show the latest semantic tag in the repo
+	// (Optional) Show the latest semantic tag in the repo; a fresh project has none.
 	latest, err := client.Tags.GetLatestTag()
-	if err != nil {
+	switch {
+	case errors.Is(err, gitlab.ErrNoTags):
+		fmt.Println("latest tag: none")
+	case err != nil:
 		log.Fatalf("[gitlab] get latest tag failed: %v", err)
+	default:
+		fmt.Printf("latest tag: %s\n", latest.String())
 	}
-	fmt.Printf("latest tag: %s\n", latest.String())
 
-	// Choose a bump. Use the enum, or parse a string if you prefer.
-	bump := version.Patch
-	// If you must start from a string:
-	// bump, err = version.ParseVersionType("patch")
-	// if err != nil { log.Fatalf("invalid bump: %v", err) }
+	// Choose a bump from the flag (or use the enum directly, e.g. version.Patch).
+	bump, err := version.ParseVersionType(*bumpFlag)
+	if err != nil {
+		log.Fatalf("invalid bump: %v", err)
+	}
 
 	current, next, err := client.Tags.GetNextVersion(bump)
 	if err != nil {